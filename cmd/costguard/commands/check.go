@@ -0,0 +1,148 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/check"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/scan"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Fail when a scan breaks cost thresholds (for CI)",
+	Long: `Evaluate a scan against cost thresholds and exit non-zero on any violation.
+
+Thresholds come from --config (JSON) and are overridden by flags. Growth and
+new-resource rules need --baseline, a scan JSON file or history ID.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		metricsPath, _ := cmd.Flags().GetString("metrics")
+		scanPath, _ := cmd.Flags().GetString("scan")
+		baselineRef, _ := cmd.Flags().GetString("baseline")
+		configPath, _ := cmd.Flags().GetString("config")
+		format, _ := cmd.Flags().GetString("format")
+
+		var t types.CheckThresholds
+		if configPath != "" {
+			raw, err := os.ReadFile(configPath)
+			if err != nil {
+				return err
+			}
+			if err := json.Unmarshal(raw, &t); err != nil {
+				return fmt.Errorf("invalid check config: %v", err)
+			}
+		}
+		if cmd.Flags().Changed("budget") {
+			t.BudgetTarget, _ = cmd.Flags().GetFloat64("budget")
+		}
+		if cmd.Flags().Changed("max-growth") {
+			t.MaxGrowthPercent, _ = cmd.Flags().GetFloat64("max-growth")
+		}
+		if cmd.Flags().Changed("max-waste") {
+			t.MaxWastePercent, _ = cmd.Flags().GetFloat64("max-waste")
+		}
+		if cmd.Flags().Changed("max-new-resource") {
+			t.MaxNewResourceCostUSD, _ = cmd.Flags().GetFloat64("max-new-resource")
+		}
+
+		var resp types.ScanResponse
+		if metricsPath != "" {
+			raw, err := os.ReadFile(metricsPath)
+			if err != nil {
+				return err
+			}
+			var req types.ScanRequest
+			if err := json.Unmarshal(raw, &req); err != nil {
+				return err
+			}
+			if resp, err = scan.RunScan(req); err != nil {
+				return err
+			}
+		} else {
+			data, err := os.ReadFile(scanPath)
+			if err != nil {
+				return fmt.Errorf("missing %s — pass --metrics or run `costguard scan` first", scanPath)
+			}
+			if err := json.Unmarshal(data, &resp); err != nil {
+				return fmt.Errorf("invalid %s: %v", scanPath, err)
+			}
+		}
+
+		var baseline *types.ScanResponse
+		if baselineRef != "" {
+			store, err := openHistory(cmd)
+			if err != nil {
+				return err
+			}
+			b, _, err := loadScan(store, baselineRef)
+			store.Close()
+			if err != nil {
+				return err
+			}
+			baseline = &b
+		}
+
+		res := check.Evaluate(resp, baseline, t)
+
+		if format == "" {
+			format = "text"
+			if os.Getenv("GITHUB_ACTIONS") == "true" {
+				format = "github"
+			}
+		}
+
+		switch format {
+		case "json":
+			if err := printJSON(res); err != nil {
+				return err
+			}
+		case "github":
+			fmt.Print(check.GitHubAnnotations(res))
+		case "text":
+			printCheckResult(res)
+		default:
+			return fmt.Errorf("unknown --format %q: use text, github or json", format)
+		}
+
+		if !res.Passed {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("cost check failed with %d violation(s)", len(res.Violations))
+		}
+		return nil
+	},
+}
+
+func printCheckResult(res types.CheckResult) {
+	fmt.Printf("💰 Total Cost: $%.2f / month", res.TotalCostUSD)
+	if res.BaselineCostUSD > 0 {
+		fmt.Printf(" (baseline $%.2f, %+.1f%%)", res.BaselineCostUSD, res.GrowthPercent)
+	}
+	fmt.Println()
+
+	if res.Passed {
+		color.Green("✔ Cost check passed")
+		return
+	}
+
+	for _, v := range res.Violations {
+		color.Red("✘ [%s] %s", v.Rule, v.Message)
+	}
+}
+
+func init() {
+	checkCmd.Flags().String("metrics", "", "Path to metrics JSON to scan (default: use --scan)")
+	checkCmd.Flags().String("scan", ".costguard/scan.json", "Path to an existing scan JSON")
+	checkCmd.Flags().String("baseline", "", "Baseline scan JSON file or history ID for growth and new-resource rules")
+	checkCmd.Flags().String("config", "", "Path to a thresholds JSON file")
+	checkCmd.Flags().Float64("budget", 0, "Maximum total monthly cost in USD")
+	checkCmd.Flags().Float64("max-growth", 0, "Maximum cost growth over the baseline in percent")
+	checkCmd.Flags().Float64("max-waste", 0, "Maximum waste percentage for any resource")
+	checkCmd.Flags().Float64("max-new-resource", 0, "Maximum monthly cost in USD for a resource not in the baseline")
+	checkCmd.Flags().String("format", "", "Output format: text, github or json (default: github on GitHub Actions, otherwise text)")
+	rootCmd.AddCommand(checkCmd)
+}
@@ -0,0 +1,146 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/diff"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/history"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [base] [head]",
+	Short: "Explain what changed between two scans",
+	Long: `Compare two scans given as scan JSON files or history IDs.
+With no arguments the two most recent scans in history are compared.`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+
+		store, err := openHistory(cmd)
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+
+		if len(args) < 2 {
+			scans, err := store.List(history.Query{Kind: history.KindScan, Limit: 2})
+			if err != nil {
+				return err
+			}
+			if len(scans) < 2 {
+				return fmt.Errorf("need two scans to compare — pass them as arguments or run `costguard scan` again")
+			}
+			args = []string{scans[1].ID, scans[0].ID}
+		}
+
+		base, baseEntry, err := loadScan(store, args[0])
+		if err != nil {
+			return err
+		}
+		head, headEntry, err := loadScan(store, args[1])
+		if err != nil {
+			return err
+		}
+
+		applied := []types.FixAction{}
+		if baseEntry != nil && headEntry != nil {
+			if applied, err = store.Applied(baseEntry.Timestamp, headEntry.Timestamp); err != nil {
+				return err
+			}
+		}
+
+		d := diff.Compare(base, head, applied)
+		d.BaseID, d.HeadID = args[0], args[1]
+
+		switch format {
+		case "json":
+			return printJSON(d)
+		case "markdown":
+			fmt.Print(diff.Markdown(d))
+		case "text":
+			printScanDiff(d)
+		default:
+			return fmt.Errorf("unknown --format %q: use text, json or markdown", format)
+		}
+		return nil
+	},
+}
+
+// loadScan reads a scan from a file, or from history when no such file
+// exists. The history entry is returned so callers can look up what happened
+// between two scans.
+func loadScan(store *history.Store, ref string) (types.ScanResponse, *history.Entry, error) {
+	var scanRes types.ScanResponse
+
+	if data, err := os.ReadFile(ref); err == nil {
+		if err := json.Unmarshal(data, &scanRes); err != nil {
+			return scanRes, nil, fmt.Errorf("invalid scan %s: %v", ref, err)
+		}
+		return scanRes, nil, nil
+	}
+
+	e, err := store.Get(ref)
+	if err != nil {
+		return scanRes, nil, fmt.Errorf("%s is neither a scan file nor a history id: %v", ref, err)
+	}
+	if e.Scan == nil {
+		return scanRes, nil, fmt.Errorf("history entry %s is a %s, not a scan", ref, e.Kind)
+	}
+	return *e.Scan, &e, nil
+}
+
+func printScanDiff(d types.ScanDiff) {
+	divider := "────────────────────────────────────────────────────────────"
+	color.Cyan("\n%s\nCOSTGUARD — SCAN DIFF (%s → %s)\n%s\n", divider, d.BaseID, d.HeadID, divider)
+
+	s := d.Summary
+	fmt.Printf("\n💰 Monthly Cost:      $%.2f → $%.2f (%s)\n", s.BaseCostUSD, s.HeadCostUSD, signedDelta(s.CostDeltaUSD))
+	fmt.Printf("💡 Potential Savings: $%.2f → $%.2f (%s)\n", s.BaseSavingsUSD, s.HeadSavingsUSD, signedDelta(s.SavingsDeltaUSD))
+	fmt.Printf("📦 Resources:         %d added, %d removed, %d changed\n", s.Added, s.Removed, s.Changed)
+
+	for _, c := range d.Resources {
+		switch c.Status {
+		case types.ChangeUnchanged:
+			continue
+		case types.ChangeAdded:
+			color.Green("\n+ %s (%s)", c.Resource, c.Provider)
+		case types.ChangeRemoved:
+			color.Red("\n- %s (%s)", c.Resource, c.Provider)
+		default:
+			color.Yellow("\n~ %s (%s)", c.Resource, c.Provider)
+		}
+
+		fmt.Printf("   Cost:      $%.2f → $%.2f (%s)\n", c.BaseCostUSD, c.HeadCostUSD, signedDelta(c.CostDeltaUSD))
+		fmt.Printf("   Waste:     %.1f%% → %.1f%%\n", c.BaseWastePct, c.HeadWastePct)
+		fmt.Printf("   Requested: %s\n", diff.RequestsChange(c.BaseRequested, c.HeadRequested, c.BaseInstanceType, c.HeadInstanceType))
+		fmt.Printf("   Usage P95: %s\n", diff.RequestsChange(c.BaseUsageP95, c.HeadUsageP95, "", ""))
+	}
+
+	if len(d.FixesApplied) > 0 {
+		fmt.Println("\n🔧 Fixes Applied:")
+		for _, f := range d.FixesApplied {
+			fmt.Printf("   • %s (%s, %s): %s\n", f.Resource, f.Intent, f.Source, f.Description)
+		}
+	}
+}
+
+func signedDelta(v float64) string {
+	if v > 0 {
+		return color.RedString("+$%.2f", v)
+	}
+	if v < 0 {
+		return color.GreenString("-$%.2f", -v)
+	}
+	return "$0.00"
+}
+
+func init() {
+	diffCmd.Flags().String("format", "text", "Output format: text, json or markdown")
+	rootCmd.AddCommand(diffCmd)
+}
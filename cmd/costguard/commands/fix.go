@@ -9,7 +9,11 @@ import (
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/ai"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/events"
 	"github.com/tanay13/costguard/packages/mcp-server/pkg/fixplan"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/github"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/history"
 	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
 	"github.com/tanay13/costguard/packages/mcp-server/pkg/utils"
 )
@@ -30,16 +34,26 @@ var fixCmd = &cobra.Command{
 		}
 
 		agg := utils.ConvertScanToAggregated(scanRes)
+		recommender, _ := cmd.Flags().GetString("recommender")
 
 		req := types.FixPlanRequest{
 			AggregatedMetrics: agg,
 			BudgetTarget:      0,
 			AutoApprove:       false,
+			Recommender:       recommender,
 		}
 
-		plan := fixplan.GenerateFixPlan(req)
+		plan, err := fixplan.GenerateFixPlan(req)
+		if err != nil {
+			return err
+		}
 
 		printFixPlan(plan)
+		recordHistory(cmd, history.Entry{Kind: history.KindFixPlan, FixPlan: &plan})
+
+		if pr, _ := cmd.Flags().GetBool("pr"); pr {
+			return openFixPR(cmd, plan)
+		}
 
 		fmt.Print("\nApply fixes using Cline? (y/n): ")
 		var choice string
@@ -50,14 +64,41 @@ var fixCmd = &cobra.Command{
 			return nil
 		}
 
+		applied := []types.FixAction{}
 		for _, action := range plan.Actions {
+			if action.RequiresApproval {
+				color.Red("\n%s (%s) is %s risk: %s", action.Resource, action.Intent, action.RiskLevel, action.Description)
+				fmt.Print("Apply this fix? (y/n): ")
+				var confirm string
+				fmt.Scanln(&confirm)
+				if confirm != "y" {
+					continue
+				}
+			}
+
 			color.Cyan("\nApplying fix for %s (%s)…", action.Resource, action.Intent)
 			color.Yellow(action.AIGuidance)
 
 			cmd := exec.Command("cline", "ai", action.AIGuidance)
 			cmd.Stdout = os.Stdout
 			cmd.Stderr = os.Stderr
-			cmd.Run()
+			if err := cmd.Run(); err != nil {
+				color.Red("✘ %s (%s) failed: %v", action.Resource, action.Intent, err)
+				events.EmitError("fix", action.Resource, fmt.Errorf("cline failed for %s: %v", action.ID, err))
+				continue
+			}
+			applied = append(applied, action)
+			events.Emit(events.Event{
+				Type:     events.FixApplied,
+				Source:   "fix",
+				Resource: action.Resource,
+				Message:  action.Description,
+				Payload:  action,
+			})
+		}
+
+		if len(applied) > 0 {
+			recordHistory(cmd, history.Entry{Kind: history.KindFixApplied, Applied: applied})
 		}
 
 		color.Green("\n✔ Fixes applied successfully.")
@@ -65,6 +106,32 @@ var fixCmd = &cobra.Command{
 	},
 }
 
+// openFixPR lets the decision engine pick the actions to apply and opens a
+// pull request with them. Both the decisions and the PR are recorded.
+func openFixPR(cmd *cobra.Command, plan types.FixPlanResponse) error {
+	summary := ai.MakeDecisions(plan)
+	recordHistory(cmd, history.Entry{Kind: history.KindDecisions, Decisions: &summary})
+	color.Cyan("\n%s", summary.Summary)
+
+	if summary.ActionsToApply == 0 {
+		color.Yellow("\nNo actions to apply — no pull request opened.")
+		return nil
+	}
+
+	base, _ := cmd.Flags().GetString("base")
+	res, err := github.CreatePR(github.PRConfig{BaseBranch: base}, plan.Actions, summary)
+	if err != nil {
+		return err
+	}
+	recordHistory(cmd, history.Entry{Kind: history.KindPR, PR: res})
+
+	color.Green("\n✔ Opened %s", res.PRURL)
+	return nil
+}
+
 func init() {
+	fixCmd.Flags().String("recommender", "", "Override the recommendation strategy used by the scan")
+	fixCmd.Flags().Bool("pr", false, "Apply the actions the decision engine approves and open a pull request instead of running Cline")
+	fixCmd.Flags().String("base", "main", "Base branch for --pr")
 	rootCmd.AddCommand(fixCmd)
 }
@@ -21,6 +21,10 @@ func printFixPlan(plan types.FixPlanResponse) {
 		color.Red("✘ Does not meet target budget\n")
 	}
 
+	if len(plan.Held) > 0 {
+		color.Yellow("⚠ Held for ongoing usage anomalies: %v\n", plan.Held)
+	}
+
 	color.Yellow("\nActions Recommended: %d\n", len(plan.Actions))
 
 	for _, a := range plan.Actions {
@@ -28,12 +32,28 @@ func printFixPlan(plan types.FixPlanResponse) {
 			Printf("\n%s\nRESOURCE: %s (%s)\n%s\n",
 				divider, a.Resource, a.Provider, divider)
 
+		fmt.Printf("ID:           %s\n", a.ID)
 		fmt.Printf("Intent:       %s\n", a.Intent)
 		fmt.Printf("Description:  %s\n", a.Description)
-		fmt.Printf("Fix Type:     %s %v %s\n",
-			a.Action.Field, a.Action.Value, a.Action.Unit)
+		if a.Action.Target != "" {
+			fmt.Printf("Fix Type:     %s → %s\n", a.Action.Field, a.Action.Target)
+		} else {
+			fmt.Printf("Fix Type:     %s %v → %v %s\n",
+				a.Action.Field, a.Action.Current, a.Action.Value, a.Action.Unit)
+		}
 		fmt.Printf("Files:        %v\n", a.FilesToEdit)
-		fmt.Printf("Savings:      $%.2f\n", a.Action.Value)
+		fmt.Printf("Savings:      $%.2f\n", a.EstimatedSavingsUSD)
+		if a.RequiresApproval {
+			color.Red("Risk:         %s (requires approval)\n", a.RiskLevel)
+		} else if a.RiskLevel != "" {
+			fmt.Printf("Risk:         %s\n", a.RiskLevel)
+		}
+		if a.RiskCategory != "" {
+			fmt.Printf("Risk Type:    %s\n", a.RiskCategory)
+		}
+		if len(a.LastActive) > 0 {
+			fmt.Printf("Last Active:  %v\n", a.LastActive)
+		}
 
 		color.Green("\nAI Guidance:\n")
 		color.White("%s\n", a.AIGuidance)
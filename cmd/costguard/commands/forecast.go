@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/forecast"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/history"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+var forecastCmd = &cobra.Command{
+	Use:   "forecast",
+	Short: "Project monthly cost from scan history",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		months, _ := cmd.Flags().GetInt("months")
+		budget, _ := cmd.Flags().GetFloat64("budget")
+		since, _ := cmd.Flags().GetDuration("since")
+		top, _ := cmd.Flags().GetInt("top")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		store, err := openHistory(cmd)
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+
+		q := history.Query{}
+		if since > 0 {
+			q.Since = time.Now().Add(-since)
+		}
+
+		resp, err := forecast.Project(store, q, months, budget)
+		if err != nil {
+			return err
+		}
+
+		if asJSON {
+			return printJSON(resp)
+		}
+		printForecast(resp, top)
+		return nil
+	},
+}
+
+func printForecast(resp types.ForecastResponse, top int) {
+	divider := "────────────────────────────────────────────────────────────"
+	color.Cyan("\n%s\nCOSTGUARD — COST FORECAST (%d months, %.0f%% interval)\n%s\n",
+		divider, resp.Horizon, resp.Confidence*100, divider)
+
+	t := resp.Total
+	fmt.Printf("\n💰 Current Cost: $%.2f / month (%d scans, trend %s / month", t.LastCostUSD, t.DataPoints, signedDelta(t.TrendUSDPerMonth))
+	if t.Seasonal {
+		fmt.Print(", weekly seasonality")
+	}
+	fmt.Println(")")
+	if resp.BudgetTarget > 0 {
+		fmt.Printf("🎯 Budget:       $%.2f / month\n", resp.BudgetTarget)
+	}
+	fmt.Println()
+
+	for _, p := range t.Months {
+		fmt.Printf("   Month %d (from %s): $%.2f  [$%.2f – $%.2f]",
+			p.Month, p.Start.Local().Format("2006-01-02"), p.CostUSD, p.LowerUSD, p.UpperUSD)
+		switch p.Budget {
+		case types.BudgetBreach:
+			color.New(color.FgRed).Print("  ✘ over budget")
+		case types.BudgetAtRisk:
+			color.New(color.FgYellow).Print("  ⚠ may exceed budget")
+		case types.BudgetOK:
+			color.New(color.FgGreen).Print("  ✔ within budget")
+		}
+		fmt.Println()
+	}
+
+	if len(resp.Resources) > 0 {
+		fmt.Println("\n📈 Resources by Trend:")
+		for i, r := range resp.Resources {
+			if top > 0 && i >= top {
+				break
+			}
+			last := r.Months[len(r.Months)-1]
+			fmt.Printf("   %-30s $%.2f → $%.2f  [$%.2f – $%.2f]  (%s / month)\n",
+				r.Key, r.LastCostUSD, last.CostUSD, last.LowerUSD, last.UpperUSD, signedDelta(r.TrendUSDPerMonth))
+		}
+	}
+
+	for _, w := range resp.Warnings {
+		color.Yellow("⚠ %s", w)
+	}
+}
+
+func init() {
+	forecastCmd.Flags().Int("months", 3, "Months to project (1-3)")
+	forecastCmd.Flags().Float64("budget", 0, "Monthly budget in USD (default: budget target of the latest fix plan in the --since window)")
+	forecastCmd.Flags().Duration("since", 0, "Only fit on scans from this far back, e.g. 2160h")
+	forecastCmd.Flags().Int("top", 10, "Resources to list (0 for all)")
+	forecastCmd.Flags().Bool("json", false, "Print JSON instead of a report")
+	rootCmd.AddCommand(forecastCmd)
+}
@@ -0,0 +1,163 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/history"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List recorded scans and fix plans, or show cost over time",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		kind, _ := cmd.Flags().GetString("kind")
+		limit, _ := cmd.Flags().GetInt("limit")
+		since, _ := cmd.Flags().GetDuration("since")
+		resource, _ := cmd.Flags().GetString("resource")
+		provider, _ := cmd.Flags().GetString("provider")
+		groupBy, _ := cmd.Flags().GetString("group-by")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		store, err := openHistory(cmd)
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+
+		q := history.Query{Kind: history.Kind(kind), Limit: limit}
+		if since > 0 {
+			q.Since = time.Now().Add(-since)
+		}
+
+		key := ""
+		switch {
+		case resource != "":
+			groupBy, key = history.GroupByResource, resource
+		case provider != "":
+			groupBy, key = history.GroupByProvider, provider
+		}
+
+		if groupBy != "" {
+			switch groupBy {
+			case history.GroupByResource, history.GroupByProvider, history.GroupByTotal:
+			default:
+				return fmt.Errorf("unknown --group-by %q: use resource, provider or total", groupBy)
+			}
+
+			series, err := store.CostSeries(q, groupBy, key)
+			if err != nil {
+				return err
+			}
+			if asJSON {
+				return printJSON(series)
+			}
+			printCostSeries(series)
+			return nil
+		}
+
+		entries, err := store.List(q)
+		if err != nil {
+			return err
+		}
+		if asJSON {
+			return printJSON(entries)
+		}
+		printHistory(entries)
+		return nil
+	},
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func printHistory(entries []history.Entry) {
+	if len(entries) == 0 {
+		color.Yellow("No history recorded yet — run `costguard scan` first.")
+		return
+	}
+
+	color.New(color.FgCyan, color.Bold).Printf("%-6s %-20s %-10s %-9s %12s %12s\n",
+		"ID", "TIME", "KIND", "COMMIT", "CURRENT", "SAVINGS")
+
+	for _, e := range entries {
+		commit := e.Repo.Commit
+		if len(commit) > 8 {
+			commit = commit[:8]
+		}
+
+		current, savings := "", ""
+		switch {
+		case e.Scan != nil:
+			current = fmt.Sprintf("$%.2f", e.Scan.Summary.TotalCurrentCostUSD)
+			savings = fmt.Sprintf("$%.2f", e.Scan.Summary.TotalPotentialSavingsUSD)
+		case e.FixPlan != nil:
+			current = fmt.Sprintf("$%.2f", e.FixPlan.TotalCurrentCost)
+			savings = fmt.Sprintf("$%.2f", e.FixPlan.TotalSavings)
+		case e.Decisions != nil:
+			savings = fmt.Sprintf("$%.2f", e.Decisions.TotalSavingsUSD)
+		}
+
+		fmt.Printf("%-6s %-20s %-10s %-9s %12s %12s",
+			e.ID, e.Timestamp.Local().Format("2006-01-02 15:04:05"), e.Kind, commit, current, savings)
+		switch {
+		case e.Decisions != nil:
+			fmt.Printf("  %d apply, %d deferred, %d skipped",
+				e.Decisions.ActionsToApply, e.Decisions.ActionsDeferred, e.Decisions.ActionsSkipped)
+		case e.PR != nil:
+			fmt.Printf("  %s", e.PR.PRURL)
+		}
+		fmt.Println()
+	}
+}
+
+func printCostSeries(series []history.CostPoint) {
+	if len(series) == 0 {
+		color.Yellow("No matching scans in history.")
+		return
+	}
+
+	color.New(color.FgCyan, color.Bold).Printf("%-6s %-20s %-30s %12s %12s %12s\n",
+		"SCAN", "TIME", "KEY", "CURRENT", "OPTIMAL", "SAVINGS")
+
+	prev := map[string]float64{}
+	for _, p := range series {
+		fmt.Printf("%-6s %-20s %-30s %12s %12s %12s",
+			p.ScanID, p.Timestamp.Local().Format("2006-01-02 15:04:05"), p.Key,
+			fmt.Sprintf("$%.2f", p.CurrentCostUSD),
+			fmt.Sprintf("$%.2f", p.OptimalCostUSD),
+			fmt.Sprintf("$%.2f", p.SavingsUSD),
+		)
+
+		if last, ok := prev[p.Key]; ok {
+			delta := p.CurrentCostUSD - last
+			switch {
+			case delta > 0:
+				color.New(color.FgRed).Printf("  ▲ $%.2f", delta)
+			case delta < 0:
+				color.New(color.FgGreen).Printf("  ▼ $%.2f", -delta)
+			}
+		}
+		fmt.Println()
+		prev[p.Key] = p.CurrentCostUSD
+	}
+}
+
+func init() {
+	historyCmd.Flags().String("kind", "", "Only show entries of this kind: scan, fix_plan, decisions, pr or fix_applied")
+	historyCmd.Flags().Int("limit", 20, "Maximum number of entries (0 for all)")
+	historyCmd.Flags().Duration("since", 0, "Only include entries from this far back, e.g. 720h")
+	historyCmd.Flags().String("resource", "", "Show cost over time for one resource")
+	historyCmd.Flags().String("provider", "", "Show cost over time for one provider")
+	historyCmd.Flags().String("group-by", "", "Show cost over time grouped by resource, provider or total")
+	historyCmd.Flags().Bool("json", false, "Print JSON instead of a table")
+	rootCmd.AddCommand(historyCmd)
+}
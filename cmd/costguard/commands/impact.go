@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/github"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/impact"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+var impactCmd = &cobra.Command{
+	Use:   "impact",
+	Short: "Estimate the monthly cost change of manifest edits (e.g. in a PR)",
+	Long: `Price the Kubernetes requests, replicas and Terraform instance types declared
+in two versions of the manifests and report the monthly cost delta.
+
+--base and --head each take a directory or a git ref.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		baseRef, _ := cmd.Flags().GetString("base")
+		headRef, _ := cmd.Flags().GetString("head")
+		format, _ := cmd.Flags().GetString("format")
+		prNumber, _ := cmd.Flags().GetInt("comment")
+
+		if baseRef == "" {
+			return fmt.Errorf("missing required flag: --base")
+		}
+
+		base, err := readManifests(baseRef)
+		if err != nil {
+			return err
+		}
+		head, err := readManifests(headRef)
+		if err != nil {
+			return err
+		}
+
+		res := impact.Estimate(base, head)
+
+		switch format {
+		case "json":
+			if err := printJSON(res); err != nil {
+				return err
+			}
+		case "markdown":
+			fmt.Print(impact.Markdown(res))
+		case "text":
+			printImpact(res)
+		default:
+			return fmt.Errorf("unknown --format %q: use text, json or markdown", format)
+		}
+
+		if prNumber > 0 {
+			if err := github.CommentOnPR(prNumber, impact.Markdown(res)); err != nil {
+				return err
+			}
+			color.Green("\n✔ Posted cost impact to PR #%d", prNumber)
+		}
+		return nil
+	},
+}
+
+func readManifests(ref string) (map[string]string, error) {
+	if info, err := os.Stat(ref); err == nil && info.IsDir() {
+		return impact.ReadDir(ref)
+	}
+	return impact.ReadGitRef(".", ref)
+}
+
+func printImpact(res types.CostImpact) {
+	divider := "────────────────────────────────────────────────────────────"
+	color.Cyan("\n%s\nCOSTGUARD — COST IMPACT\n%s\n", divider, divider)
+
+	if len(res.Changes) == 0 {
+		fmt.Println("\nNo change to declared resource costs.")
+		return
+	}
+
+	fmt.Printf("\n💰 Monthly Cost: $%.2f → $%.2f (%s, %+.1f%%)\n\n",
+		res.BaseCostUSD, res.HeadCostUSD, signedDelta(res.DeltaUSD), res.DeltaPercent)
+
+	for _, c := range res.Changes {
+		name := impact.DisplayName(c)
+		fmt.Printf("   %-9s %s %s: %s\n", c.Status, c.Kind, name, signedDelta(c.CostDeltaUSD))
+	}
+
+	for _, u := range res.Unpriced {
+		color.Yellow("⚠ %s", u)
+	}
+}
+
+func init() {
+	impactCmd.Flags().String("base", "", "Base manifests: a directory or git ref (e.g. origin/main)")
+	impactCmd.Flags().String("head", ".", "Head manifests: a directory or git ref")
+	impactCmd.Flags().String("format", "text", "Output format: text, json or markdown")
+	impactCmd.Flags().Int("comment", 0, "Post the Markdown report as a comment on this PR number via gh")
+	rootCmd.AddCommand(impactCmd)
+}
@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/allocation"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/scan"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/utils"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Allocate cost by label for showback or chargeback",
+	Long: `Group a scan's cost by one or more labels or tags, e.g. --group-by team
+or --group-by team,env.
+
+Resources missing a label are reported as (unlabelled). Pass
+--unlabelled distribute to charge that spend to the labelled groups in
+proportion to their cost instead.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		metricsPath, _ := cmd.Flags().GetString("metrics")
+		scanRef, _ := cmd.Flags().GetString("scan")
+		groupBy, _ := cmd.Flags().GetString("group-by")
+		unlabelled, _ := cmd.Flags().GetString("unlabelled")
+		format, _ := cmd.Flags().GetString("format")
+
+		if groupBy == "" {
+			return fmt.Errorf("missing required flag: --group-by")
+		}
+
+		var resp types.ScanResponse
+		if metricsPath != "" {
+			raw, err := os.ReadFile(metricsPath)
+			if err != nil {
+				return err
+			}
+			var req types.ScanRequest
+			if err := json.Unmarshal(raw, &req); err != nil {
+				return err
+			}
+			if resp, err = scan.RunScan(req); err != nil {
+				return err
+			}
+		} else {
+			store, err := openHistory(cmd)
+			if err != nil {
+				return err
+			}
+			resp, _, err = loadScan(store, scanRef)
+			store.Close()
+			if err != nil {
+				return err
+			}
+		}
+
+		report, err := allocation.Allocate(utils.ConvertScanToAggregated(resp), groupBy, unlabelled)
+		if err != nil {
+			return err
+		}
+
+		switch format {
+		case "json":
+			return printJSON(report)
+		case "csv":
+			out, err := allocation.CSV(report)
+			if err != nil {
+				return err
+			}
+			fmt.Print(out)
+		case "markdown":
+			fmt.Print(allocation.Markdown(report))
+		case "text":
+			printAllocation(report)
+		default:
+			return fmt.Errorf("unknown --format %q: use text, csv, json or markdown", format)
+		}
+		return nil
+	},
+}
+
+func printAllocation(r types.AllocationReport) {
+	divider := "────────────────────────────────────────────────────────────"
+	color.Cyan("\n%s\nCOSTGUARD — COST ALLOCATION BY %s\n%s\n", divider, strings.ToUpper(strings.Join(r.GroupBy, "/")), divider)
+
+	fmt.Printf("\n💰 Total Cost: $%.2f / month\n", r.TotalCostUSD)
+	if r.UnlabelledCostUSD > 0 {
+		msg := "reported separately"
+		if r.Unlabelled == types.UnlabelledDistribute {
+			msg = "distributed by cost share"
+		}
+		color.Yellow("⚠ Unlabelled: $%.2f / month (%s)\n", r.UnlabelledCostUSD, msg)
+	}
+	fmt.Println()
+
+	for _, g := range r.Groups {
+		fmt.Printf("   %-30s $%10.2f  %5.1f%%  (%d resources", g.Value, g.TotalCostUSD, g.SharePercent, len(g.Resources))
+		if g.OverheadCostUSD != 0 {
+			fmt.Printf(", $%.2f cluster overhead", g.OverheadCostUSD)
+		}
+		if g.SharedCostUSD > 0 {
+			fmt.Printf(", $%.2f unlabelled share", g.SharedCostUSD)
+		}
+		if g.SavingsUSD > 0 {
+			fmt.Printf(", $%.2f savable", g.SavingsUSD)
+		}
+		fmt.Println(")")
+	}
+}
+
+func init() {
+	reportCmd.Flags().String("metrics", "", "Path to metrics JSON to scan instead of using an existing scan")
+	reportCmd.Flags().String("scan", ".costguard/scan.json", "Scan JSON file or history ID")
+	reportCmd.Flags().String("group-by", "", "Label keys to group by, comma-separated (e.g. team or team,env)")
+	reportCmd.Flags().String("unlabelled", types.UnlabelledKeep, "How to handle unlabelled spend: keep or distribute")
+	reportCmd.Flags().String("format", "text", "Output format: text, csv, json or markdown")
+	rootCmd.AddCommand(reportCmd)
+}
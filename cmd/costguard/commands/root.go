@@ -1,7 +1,12 @@
 package commands
 
 import (
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/events"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/history"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/pricing"
 )
 
 var rootCmd = &cobra.Command{
@@ -16,7 +21,87 @@ Run cost analysis:
 
 Generate and apply fix plans:
     costguard fix
+
+Export VerticalPodAutoscaler manifests:
+    costguard scan --metrics metrics.json --recommender vpa
+    costguard vpa --mode Off
+
+Simulate node savings from recommended requests:
+    costguard simulate --nodes nodes.json
+
+Show cost over time from recorded scans:
+    costguard history --group-by provider
+
+Explain what changed between two scans:
+    costguard diff 3 7 --format markdown
+
+Gate CI on cost thresholds:
+    costguard check --metrics metrics.json --budget 500 --baseline main-scan.json --max-growth 10
+
+Estimate the cost impact of a PR's manifest changes:
+    costguard impact --base origin/main --format markdown
+
+Forecast cost for the next months from history:
+    costguard forecast --months 3 --budget 500
+
+Allocate cost by team for chargeback:
+    costguard report --group-by team --format csv
 `,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		eventsPath, _ := cmd.Flags().GetString("events-log")
+		if eventsPath != "" {
+			log, err := events.Open(eventsPath)
+			if err != nil {
+				return err
+			}
+			events.Default = log
+		}
+
+		path, _ := cmd.Flags().GetString("pricing")
+		if path == "" {
+			return nil
+		}
+
+		catalog, err := pricing.Load(path)
+		if err != nil {
+			return err
+		}
+		pricing.Default = catalog
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().String("pricing", "", "Path to a pricing catalog JSON overriding the built-in prices")
+	rootCmd.PersistentFlags().String("history-db", history.DefaultPath, "Path to the scan history database")
+	rootCmd.PersistentFlags().String("events-log", events.DefaultPath, "Path to the JSONL event log (empty to disable)")
+}
+
+func openHistory(cmd *cobra.Command) (*history.Store, error) {
+	path, _ := cmd.Flags().GetString("history-db")
+	return history.Open(path)
+}
+
+// recordHistory appends e to the history database. Failing to record never
+// fails the command.
+func recordHistory(cmd *cobra.Command, e history.Entry) {
+	store, err := openHistory(cmd)
+	if err != nil {
+		color.Yellow("⚠ history not recorded: %v", err)
+		return
+	}
+	defer store.Close()
+
+	if e.Kind != history.KindScan && e.ScanID == "" {
+		if last, ok, _ := store.Latest(history.KindScan); ok {
+			e.ScanID = last.ID
+		}
+	}
+	e.Repo = history.DetectRepo(".")
+
+	if _, err := store.Add(e); err != nil {
+		color.Yellow("⚠ history not recorded: %v", err)
+	}
 }
 
 func Execute() error {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/history"
 	"github.com/tanay13/costguard/packages/mcp-server/pkg/scan"
 	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
 )
@@ -31,6 +32,30 @@ var scanCmd = &cobra.Command{
 			return err
 		}
 
+		if recommender, _ := cmd.Flags().GetString("recommender"); recommender != "" {
+			req.Recommender = recommender
+		}
+
+		if nodesPath, _ := cmd.Flags().GetString("nodes"); nodesPath != "" {
+			raw, err := os.ReadFile(nodesPath)
+			if err != nil {
+				return err
+			}
+			if req.Cluster == nil {
+				req.Cluster = &types.ClusterCost{}
+			}
+			if err := json.Unmarshal(raw, &req.Cluster.Nodes); err != nil {
+				return fmt.Errorf("invalid node inventory: %v", err)
+			}
+		}
+		if req.Cluster != nil {
+			if strategy, _ := cmd.Flags().GetString("overhead"); strategy != "" {
+				req.Cluster.Strategy = strategy
+			}
+			shared, _ := cmd.Flags().GetStringSlice("shared")
+			req.Cluster.SharedResources = append(req.Cluster.SharedResources, shared...)
+		}
+
 		resp, err := scan.RunScan(req)
 		if err != nil {
 			return err
@@ -41,6 +66,7 @@ var scanCmd = &cobra.Command{
 		out, _ := json.MarshalIndent(resp, "", "  ")
 		os.MkdirAll(".costguard", 0755)
 		os.WriteFile(".costguard/scan.json", out, 0644)
+		recordHistory(cmd, history.Entry{Kind: history.KindScan, Scan: &resp})
 
 		color.Green("\n✔ Scan complete → .costguard/scan.json")
 
@@ -50,5 +76,9 @@ var scanCmd = &cobra.Command{
 
 func init() {
 	scanCmd.Flags().String("metrics", "", "Path to metrics JSON")
+	scanCmd.Flags().String("recommender", "", "Recommendation strategy: conservative, balanced, aggressive or vpa")
+	scanCmd.Flags().String("nodes", "", "Path to a node inventory JSON; spreads shared and idle node cost over workloads")
+	scanCmd.Flags().String("overhead", "", "How to spread cluster overhead: proportional, even or weighted")
+	scanCmd.Flags().StringSlice("shared", nil, "Workloads whose cost is shared overhead (DaemonSets always are)")
 	rootCmd.AddCommand(scanCmd)
 }
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/fatih/color"
@@ -27,6 +28,13 @@ func printScanReport(resp types.ScanResponse) {
 	)
 
 	
+	if c := resp.Summary.Cluster; c != nil {
+		fmt.Printf("🖥  Cluster Nodes:        $%.2f / month (%s split)\n", c.NodeCostUSD, c.Strategy)
+		fmt.Printf("   Workload Requests:   $%.2f\n", c.WorkloadCostUSD)
+		fmt.Printf("   Shared Workloads:    $%.2f\n", c.SharedCostUSD)
+		fmt.Printf("   Idle Capacity:       $%.2f\n\n", c.IdleCostUSD)
+	}
+
 	if len(resp.Summary.TopOffenders) > 0 {
 		fmt.Println("🔥 Top Offenders:")
 		for i, name := range resp.Summary.TopOffenders {
@@ -47,11 +55,38 @@ func printResourceDetail(r types.ScanResource) {
 		divider, r.Resource, r.Provider, divider)
 
 	
+	if len(r.Anomalies) > 0 {
+		printAnomalies(r.Anomalies)
+	}
+
+	if r.Network != nil {
+		printNetworkDetail(r)
+	}
+
+	switch {
+	case r.Provider == types.ProviderNetworkFlow:
+		printCostDetail(r)
+		return
+	case r.Storage != nil:
+		printStorageDetail(r)
+		return
+	case r.Serverless != nil:
+		printServerlessDetail(r)
+		return
+	case r.Machine != nil:
+		printMachineDetail(r)
+		return
+	case r.Bucket != nil:
+		printBucketDetail(r)
+		return
+	}
+
 	fmt.Printf("CPU Usage (milli):\n")
 	fmt.Printf("   P50:      %.0fm\n", r.Usage["cpu_milli"].P50)
 	fmt.Printf("   P95:      %.0fm\n", r.Usage["cpu_milli"].P95)
 	fmt.Printf("   Average:  %.0fm\n", r.Usage["cpu_milli"].Avg)
 	fmt.Printf("   Requested: %.0fm\n", r.Requested.CpuMilli)
+	fmt.Printf("   Recommended: %.0fm\n", r.Recommended.CpuMilli)
 	fmt.Printf("   Waste:     %.1f%%\n\n", r.Costs.WastePercentage)
 
 	
@@ -59,6 +94,7 @@ func printResourceDetail(r types.ScanResource) {
 	fmt.Printf("   P50:      %.2f GB\n", r.Usage["memory_gb"].P50)
 	fmt.Printf("   P95:      %.2f GB\n", r.Usage["memory_gb"].P95)
 	fmt.Printf("   Requested: %.2f GB\n", r.Requested.MemoryGB)
+	fmt.Printf("   Recommended: %.2f GB\n", r.Recommended.MemoryGB)
 	fmt.Printf("   Waste:     %.1f%%\n\n", r.Costs.WastePercentage)
 
 	
@@ -66,4 +102,167 @@ func printResourceDetail(r types.ScanResource) {
 	fmt.Printf("   Current:  $%.2f\n", r.Costs.CurrentCostUSD)
 	fmt.Printf("   Optimal:  $%.2f\n", r.Costs.OptimalCostUSD)
 	fmt.Printf("   Savings:  $%.2f\n\n", r.Costs.PotentialSavingsUSD)
+
+	if o := r.Overhead; o != nil {
+		fmt.Printf("Cluster Overhead:\n")
+		if o.Shared {
+			fmt.Printf("   Shared workload, cost spread over the others\n\n")
+			return
+		}
+		fmt.Printf("   Shared:    $%.2f\n", o.SharedCostUSD)
+		fmt.Printf("   Idle:      $%.2f\n", o.IdleCostUSD)
+		fmt.Printf("   Allocated: $%.2f\n\n", o.AllocatedCostUSD)
+	}
+}
+
+func printServerlessDetail(r types.ScanResource) {
+	sv := r.Serverless
+
+	if r.Provider == types.ProviderAzureContainerApps {
+		fmt.Printf("Container App:\n")
+		fmt.Printf("   CPU:           %g → %g vCPU (P95 %.2f vCPU)\n", sv.CpuLimit, sv.RecommendedCpuLimit, r.Usage["cpu_usage"].P95)
+		fmt.Printf("   Memory:        %g → %g Gi (P95 %.2f GB)\n", sv.MemoryLimitGB, sv.RecommendedMemoryLimitGB, r.Usage["memory_usage_gb"].P95)
+		fmt.Printf("   Min Replicas:  %.0f → %.0f\n", sv.MinInstances, sv.RecommendedMinInstances)
+		fmt.Printf("   Replicas:      %.1f avg\n", r.Usage["replicas"].Avg)
+		fmt.Printf("   Billed:        %.2f vCPU, %.2f GiB avg\n\n", r.Usage["vcpu"].Avg, r.Usage["gib"].Avg)
+
+		printCostDetail(r)
+		return
+	}
+
+	fmt.Printf("Service:\n")
+	fmt.Printf("   CPU Limit:     %g → %g vCPU (P95 %.0f%%)\n", sv.CpuLimit, sv.RecommendedCpuLimit, r.Usage["cpu_utilization"].P95)
+	fmt.Printf("   Memory Limit:  %.2f → %.2f GB (P95 %.0f%%)\n", sv.MemoryLimitGB, sv.RecommendedMemoryLimitGB, r.Usage["memory_utilization"].P95)
+	fmt.Printf("   Concurrency:   %.0f → %.0f\n", sv.Concurrency, sv.RecommendedConcurrency)
+	fmt.Printf("   Min Instances: %.0f → %.0f\n", sv.MinInstances, sv.RecommendedMinInstances)
+	fmt.Printf("   Instances:     %.1f avg\n\n", r.Usage["instances"].Avg)
+
+	printCostDetail(r)
+}
+
+func printMachineDetail(r types.ScanResource) {
+	m := r.Machine
+
+	switch r.Provider {
+	case types.ProviderAWSECSFargate:
+		fmt.Printf("Task Size:\n")
+	case types.ProviderAWSRDS:
+		fmt.Printf("Instance Class:\n")
+	default:
+		fmt.Printf("Machine:\n")
+	}
+	fmt.Printf("   Type:      %s → %s\n", m.InstanceType, m.RecommendedType)
+	fmt.Printf("   vCPU:      %g → %g (P95 %.0f%%)\n", m.VCPU, m.RecommendedVCPU, r.Usage["cpu_percent"].P95)
+	fmt.Printf("   Memory:    %g → %g GB (P95 %.0f%%)\n\n", m.MemoryGB, m.RecommendedMemoryGB, r.Usage["memory_percent"].P95)
+
+	if db := r.Database; db != nil {
+		fmt.Printf("Database:\n")
+		fmt.Printf("   Storage:     %s → %s (%.0f GB)\n", db.StorageType, db.RecommendedStorageType, db.AllocatedStorageGB)
+		if db.ProvisionedIOPS > 0 {
+			fmt.Printf("   IOPS:        %.0f → %.0f (P95 %.0f)\n", db.ProvisionedIOPS, db.RecommendedIOPS, r.Usage["iops"].P95)
+		}
+		fmt.Printf("   Connections: P95 %.0f, max %.0f → %.0f\n", r.Usage["connections"].P95, db.MaxConnections, db.RecommendedMaxConnections)
+		fmt.Printf("   Multi-AZ:    %t\n\n", db.MultiAZ)
+	}
+
+	printCostDetail(r)
+}
+
+func printCostDetail(r types.ScanResource) {
+	fmt.Printf("Cost:\n")
+	fmt.Printf("   Current:  $%.2f\n", r.Costs.CurrentCostUSD)
+	fmt.Printf("   Optimal:  $%.2f\n", r.Costs.OptimalCostUSD)
+	fmt.Printf("   Savings:  $%.2f\n\n", r.Costs.PotentialSavingsUSD)
+}
+
+func printStorageDetail(r types.ScanResource) {
+	st := r.Storage
+
+	fmt.Printf("Storage:\n")
+	fmt.Printf("   Class:     %s", st.StorageClass)
+	if st.RecommendedClass != st.StorageClass {
+		fmt.Printf(" → %s", st.RecommendedClass)
+	}
+	fmt.Println()
+	fmt.Printf("   Capacity:  %.0f GB → %.0f GB\n", st.CapacityGB, st.RecommendedCapacityGB)
+	fmt.Printf("   Used P95:  %.1f GB\n", r.Usage["used_gb"].P95)
+	if st.Owner != "" {
+		fmt.Printf("   Owner:     %s/%s\n", st.OwnerKind, st.Owner)
+	}
+	if st.Unbound {
+		color.Red("   ✘ Unbound (%s)\n", st.Phase)
+	}
+	if st.Orphaned {
+		color.Red("   ✘ Not mounted by any pod\n")
+	}
+	fmt.Println()
+
+	printCostDetail(r)
+}
+
+func printBucketDetail(r types.ScanResource) {
+	b := r.Bucket
+
+	classes := make([]string, 0, len(b.StorageGB))
+	for class := range b.StorageGB {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+
+	fmt.Printf("Bucket:\n")
+	for _, class := range classes {
+		fmt.Printf("   %-14s %.1f GB\n", class+":", b.StorageGB[class])
+	}
+	if b.IncompleteMultipartGB > 0 {
+		fmt.Printf("   %-14s %.1f GB\n", "Multipart:", b.IncompleteMultipartGB)
+	}
+	fmt.Printf("   GET/month:     %.0f\n", b.MonthlyGetRequests)
+	fmt.Printf("   Egress/month:  %.1f GB\n\n", b.MonthlyDownloadedGB)
+
+	if len(b.RecommendedRules) > 0 {
+		fmt.Printf("Lifecycle Rules:\n")
+		for _, rule := range b.RecommendedRules {
+			target := rule.StorageClass
+			if target == "" {
+				target = "abort multipart"
+			}
+			fmt.Printf("   %-3d days → %-16s %.1f GB  $%.2f\n", rule.Days, target, rule.SizeGB, rule.SavingsUSD)
+		}
+		fmt.Println()
+	}
+
+	printCostDetail(r)
+}
+
+func printAnomalies(anomalies []types.Anomaly) {
+	fmt.Println("Anomalies:")
+	for _, a := range anomalies {
+		c := color.New(color.FgYellow)
+		if a.Severity == types.SeverityCritical {
+			c = color.New(color.FgRed)
+		}
+		suffix := ""
+		if a.Ongoing {
+			suffix = " (ongoing, rightsizing held)"
+		}
+		c.Printf("   ⚠ [%s] %s%s\n", a.Severity, a.Description, suffix)
+	}
+	fmt.Println()
+}
+
+func printNetworkDetail(r types.ScanResource) {
+	n := r.Network
+
+	fmt.Printf("Network ($%.2f / month):\n", n.CostUSD)
+	fmt.Printf("   Internet:      %.1f GB\n", n.InternetGB)
+	fmt.Printf("   Cross-zone:    %.1f GB\n", n.CrossZoneGB)
+	fmt.Printf("   Cross-region:  %.1f GB\n", n.CrossRegionGB)
+	for _, f := range n.Flows {
+		marker := ""
+		if f.TopologyAware {
+			marker = "  ← keep in-zone"
+		}
+		fmt.Printf("   → %-20s %-12s %8.1f GB  $%.2f%s\n", f.Destination, f.Kind, f.MonthlyGB, f.CostUSD, marker)
+	}
+	fmt.Println()
 }
@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/simulate"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+var simulateCmd = &cobra.Command{
+	Use:   "simulate",
+	Short: "Simulate node bin-packing to estimate cluster-level savings",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		nodesPath, _ := cmd.Flags().GetString("nodes")
+		podsPath, _ := cmd.Flags().GetString("pods")
+
+		if nodesPath == "" {
+			return fmt.Errorf("missing required flag: --nodes")
+		}
+
+		raw, err := os.ReadFile(nodesPath)
+		if err != nil {
+			return err
+		}
+
+		var req types.SimulationRequest
+		if err := json.Unmarshal(raw, &req.Nodes); err != nil {
+			return fmt.Errorf("invalid node inventory: %v", err)
+		}
+
+		if podsPath != "" {
+			raw, err := os.ReadFile(podsPath)
+			if err != nil {
+				return err
+			}
+			if err := json.Unmarshal(raw, &req.Pods); err != nil {
+				return fmt.Errorf("invalid pods file: %v", err)
+			}
+		} else {
+			data, err := os.ReadFile(".costguard/scan.json")
+			if err != nil {
+				return fmt.Errorf("missing scan.json — run `costguard scan` first or pass --pods")
+			}
+
+			var scanRes types.ScanResponse
+			if err := json.Unmarshal(data, &scanRes); err != nil {
+				return fmt.Errorf("invalid scan.json: %v", err)
+			}
+			req.Scan = &scanRes
+		}
+
+		resp, err := simulate.RunSimulation(req)
+		if err != nil {
+			return err
+		}
+
+		printSimulation(resp)
+		return nil
+	},
+}
+
+func printSimulation(resp types.SimulationResponse) {
+	divider := "────────────────────────────────────────────────────────────"
+
+	color.Cyan("\n%s\nCOSTGUARD — NODE SIMULATION\n%s\n", divider, divider)
+
+	fmt.Printf("\n🖥  Fleet:              %d nodes ($%.2f / month)\n", resp.FleetNodes, resp.FleetCostUSD)
+	fmt.Printf("📦 Current Packing:    %d nodes ($%.2f / month)\n", resp.Current.NodesUsed, resp.Current.CostUSD)
+	fmt.Printf("🎯 Recommended:        %d nodes ($%.2f / month)\n", resp.Recommended.NodesUsed, resp.Recommended.CostUSD)
+	fmt.Printf("💡 Nodes Drainable:    %d\n", resp.NodesDrained)
+	fmt.Printf("💰 Node Cost Savings:  $%.2f / month\n", resp.CostDeltaUSD)
+	fmt.Printf("🧮 Per-Pod Estimate:   $%.2f / month\n", resp.PerPodSavingsUSD)
+
+	instanceTypes := []string{}
+	for t := range resp.Recommended.NodesByType {
+		instanceTypes = append(instanceTypes, t)
+	}
+	sort.Strings(instanceTypes)
+	if len(instanceTypes) > 0 {
+		fmt.Println("\nRecommended Nodes by Type:")
+		for _, t := range instanceTypes {
+			fmt.Printf("   %s: %d (was %d)\n", t, resp.Recommended.NodesByType[t], resp.Current.NodesByType[t])
+		}
+	}
+
+	if len(resp.Recommended.Unschedulable) > 0 {
+		color.Red("\n✘ Unschedulable with recommended requests: %v\n", resp.Recommended.Unschedulable)
+	}
+	if len(resp.Current.Unschedulable) > 0 {
+		color.Red("✘ Unschedulable with current requests: %v\n", resp.Current.Unschedulable)
+	}
+}
+
+func init() {
+	simulateCmd.Flags().String("nodes", "", "Path to node inventory JSON")
+	simulateCmd.Flags().String("pods", "", "Path to pod requests JSON (default: .costguard/scan.json)")
+	rootCmd.AddCommand(simulateCmd)
+}
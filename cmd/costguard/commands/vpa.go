@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/provider/kubernetes"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+var vpaCmd = &cobra.Command{
+	Use:   "vpa",
+	Short: "Export scan recommendations as VerticalPodAutoscaler manifests",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mode, _ := cmd.Flags().GetString("mode")
+		kind, _ := cmd.Flags().GetString("kind")
+		outDir, _ := cmd.Flags().GetString("out")
+
+		data, err := os.ReadFile(".costguard/scan.json")
+		if err != nil {
+			return fmt.Errorf("missing scan.json — run `costguard scan --recommender vpa` first")
+		}
+
+		var scanRes types.ScanResponse
+		if err := json.Unmarshal(data, &scanRes); err != nil {
+			return fmt.Errorf("invalid scan.json: %v", err)
+		}
+
+		for _, r := range scanRes.Resources {
+			if r.Provider != types.ProviderKubernetes {
+				continue
+			}
+
+			bounds := types.RecommendationBounds{}
+			if r.Bounds != nil {
+				bounds = *r.Bounds
+			}
+
+			manifest, err := kubernetes.RenderVPA(r.Resource, kind, mode, bounds)
+			if err != nil {
+				return err
+			}
+
+			if outDir == "" {
+				fmt.Printf("---\n%s", manifest)
+				continue
+			}
+
+			if err := os.MkdirAll(outDir, 0755); err != nil {
+				return err
+			}
+			path := filepath.Join(outDir, r.Resource+"-vpa.yaml")
+			if err := os.WriteFile(path, []byte(manifest), 0644); err != nil {
+				return err
+			}
+			color.Green("✔ %s", path)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	vpaCmd.Flags().String("mode", kubernetes.VPAModeOff, "VPA update mode: Off or Initial")
+	vpaCmd.Flags().String("kind", "Deployment", "Kind of the workload the VPA targets")
+	vpaCmd.Flags().String("out", "", "Directory to write manifests to (default: stdout)")
+	rootCmd.AddCommand(vpaCmd)
+}
@@ -1,16 +1,54 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
+	"log"
 	"net/http"
 	"os"
 	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/ai"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/allocation"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/diff"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/events"
 	"github.com/tanay13/costguard/packages/mcp-server/pkg/fixplan"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/forecast"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/github"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/history"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/impact"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/pricing"
 	"github.com/tanay13/costguard/packages/mcp-server/pkg/scan"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/simulate"
 	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/utils"
 )
 
+var (
+	historyPath string
+	historyMu   sync.Mutex
+)
+
+// withHistory opens the history store for the duration of fn. The store is
+// not held open between requests so the CLI can record runs while the
+// server is up; requests take turns because bbolt locks the whole file.
+func withHistory(fn func(*history.Store) error) error {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	store, err := history.Open(historyPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	return fn(store)
+}
+
 func HealthHandler(c *gin.Context) {
 	c.JSON(200, gin.H{
 		"message": "codeguard is up..",
@@ -20,8 +58,13 @@ func HealthHandler(c *gin.Context) {
 
 func ScanHandler(c *gin.Context) {
 	var req struct {
-		Metrics        []types.MetricCollection  `json:"metrics"`
-		ActualRequests map[string]types.Requests `json:"actual_requests"`
+		Metrics        []types.MetricCollection      `json:"metrics"`
+		ActualRequests map[string]types.Requests     `json:"actual_requests"`
+		Autoscalers    map[string]types.HPAConfig    `json:"autoscalers"`
+		Workloads      map[string]types.WorkloadInfo `json:"workloads"`
+		Recommender    string                        `json:"recommender"`
+		Cluster        *types.ClusterCost            `json:"cluster"`
+		Repo           *history.Repo                 `json:"repo"`
 	}
 
 	if err := c.BindJSON(&req); err != nil {
@@ -31,21 +74,147 @@ func ScanHandler(c *gin.Context) {
 	resp, err := scan.RunScan(types.ScanRequest{
 		Metrics:        req.Metrics,
 		ActualRequests: req.ActualRequests,
+		Autoscalers:    req.Autoscalers,
+		Workloads:      req.Workloads,
+		Recommender:    req.Recommender,
+		Cluster:        req.Cluster,
 	})
 	if err != nil {
 		c.JSON(500, gin.H{"error": err.Error()})
 		return
 	}
 
+	repo := history.DetectRepo(".")
+	if req.Repo != nil {
+		repo = *req.Repo
+	}
+	err = withHistory(func(store *history.Store) error {
+		_, err := store.Add(history.Entry{Kind: history.KindScan, Repo: repo, Scan: &resp})
+		return err
+	})
+	if err != nil {
+		log.Printf("failed to record scan: %v", err)
+	}
+
 	c.JSON(200, resp)
 }
 
+// ScanDiffHandler compares two scans given inline or by history ID. Fixes
+// recorded between two history entries are reported as applied. Pass
+// ?format=markdown for a Markdown report.
+func ScanDiffHandler(c *gin.Context) {
+	var req types.ScanDiffRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	resolve := func(store *history.Store, inline *types.ScanResponse, id string) (types.ScanResponse, *history.Entry, error) {
+		if inline != nil {
+			return *inline, nil, nil
+		}
+		if id == "" {
+			return types.ScanResponse{}, nil, fmt.Errorf("each side needs a scan or a history id")
+		}
+		e, err := store.Get(id)
+		if err != nil {
+			return types.ScanResponse{}, nil, err
+		}
+		if e.Scan == nil {
+			return types.ScanResponse{}, nil, fmt.Errorf("history entry %s is not a scan", id)
+		}
+		return *e.Scan, &e, nil
+	}
+
+	var base, head types.ScanResponse
+	applied := []types.FixAction{}
+	status := 400
+	err := withHistory(func(store *history.Store) error {
+		var baseEntry, headEntry *history.Entry
+		var err error
+		base, baseEntry, err = resolve(store, req.Base, req.BaseID)
+		if err != nil {
+			return err
+		}
+		head, headEntry, err = resolve(store, req.Head, req.HeadID)
+		if err != nil {
+			return err
+		}
+
+		if baseEntry != nil && headEntry != nil {
+			status = 500
+			applied, err = store.Applied(baseEntry.Timestamp, headEntry.Timestamp)
+		}
+		return err
+	})
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	d := diff.Compare(base, head, applied)
+	d.BaseID, d.HeadID = req.BaseID, req.HeadID
+
+	if c.Query("format") == "markdown" {
+		c.Data(200, "text/markdown; charset=utf-8", []byte(diff.Markdown(d)))
+		return
+	}
+	c.JSON(200, d)
+}
+
+// ImpactHandler prices the manifests of a change's base and head. Pass
+// ?format=markdown for a PR comment body.
+func ImpactHandler(c *gin.Context) {
+	var req types.ImpactRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	res := impact.Estimate(req.Base, req.Head)
+
+	if c.Query("format") == "markdown" {
+		c.Data(200, "text/markdown; charset=utf-8", []byte(impact.Markdown(res)))
+		return
+	}
+	c.JSON(200, res)
+}
+
+// ReportHandler allocates a scan's cost by label. Pass ?format=csv or
+// ?format=markdown for a chargeback report instead of JSON.
+func ReportHandler(c *gin.Context) {
+	var req types.ReportRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := allocation.Allocate(utils.ConvertScanToAggregated(req.Scan), req.GroupBy, req.Unlabelled)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch c.Query("format") {
+	case "csv":
+		out, err := allocation.CSV(report)
+		if err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		c.Data(200, "text/csv; charset=utf-8", []byte(out))
+	case "markdown":
+		c.Data(200, "text/markdown; charset=utf-8", []byte(allocation.Markdown(report)))
+	default:
+		c.JSON(200, report)
+	}
+}
+
 func buildScanResponse(agg []types.AggregatedMetrics) types.ScanResponse {
 	resp := types.ScanResponse{}
 	totalCurrent := 0.0
 	totalOptimal := 0.0
 
-
 	temp := []struct {
 		name    string
 		savings float64
@@ -110,29 +279,365 @@ func FixPlansHandler(c *gin.Context) {
 		return
 	}
 
-	resp := fixplan.GenerateFixPlan(req)
+	resp, err := fixplan.GenerateFixPlan(req)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	recordHistory(history.Entry{Kind: history.KindFixPlan, FixPlan: &resp})
+
+	c.JSON(200, resp)
+}
+
+// DecisionsHandler decides which actions of a fix plan to apply, defer or
+// skip.
+func DecisionsHandler(c *gin.Context) {
+	var plan types.FixPlanResponse
+	if err := c.BindJSON(&plan); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	summary := ai.MakeDecisions(plan)
+	recordHistory(history.Entry{Kind: history.KindDecisions, Decisions: &summary})
+
+	c.JSON(200, summary)
+}
+
+// PRHandler applies the actions decided "apply" on a new branch of the
+// server's working copy and opens a pull request for them.
+func PRHandler(c *gin.Context) {
+	var req struct {
+		Actions    []types.FixAction       `json:"actions"`
+		Decisions  types.AIDecisionSummary `json:"decisions"`
+		BaseBranch string                  `json:"base_branch"`
+	}
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if req.BaseBranch == "" {
+		req.BaseBranch = "main"
+	}
+
+	res, err := github.CreatePR(github.PRConfig{BaseBranch: req.BaseBranch}, req.Actions, req.Decisions)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	recordHistory(history.Entry{Kind: history.KindPR, PR: res})
+
+	c.JSON(200, res)
+}
+
+// recordHistory adds e for the current repo, linked to the latest scan.
+// Failing to record never fails the request.
+func recordHistory(e history.Entry) {
+	e.Repo = history.DetectRepo(".")
+	err := withHistory(func(store *history.Store) error {
+		if last, ok, _ := store.Latest(history.KindScan); ok {
+			e.ScanID = last.ID
+		}
+		_, err := store.Add(e)
+		return err
+	})
+	if err != nil {
+		log.Printf("failed to record %s: %v", e.Kind, err)
+	}
+}
+
+func SimulateHandler(c *gin.Context) {
+	var req types.SimulationRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := simulate.RunSimulation(req)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
 	c.JSON(200, resp)
 }
 
+// EventsHandler pages through the event log, oldest first. Query params:
+// type (comma-separated), source, resource, since and until (RFC3339),
+// after (the next cursor of the previous page) and limit.
 func EventsHandler(c *gin.Context) {
-	jsonBytes, err := os.ReadFile("events.json")
+	q, err := eventsQuery(c)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := events.Default.List(q)
 	if err != nil {
-		if os.IsNotExist(err) {
-			c.JSON(http.StatusNotFound, gin.H{"message": "No file found at that path"})
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, page)
+}
+
+// EventsStreamHandler pushes events as Server-Sent Events, filtered like
+// EventsHandler. A client resuming with Last-Event-ID (or ?last_event_id=)
+// first gets the events it missed from the log. Clients that fall too far
+// behind are disconnected and resume the same way.
+func EventsStreamHandler(c *gin.Context) {
+	q, err := eventsQuery(c)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	lastID := c.GetHeader("Last-Event-ID")
+	if lastID == "" {
+		lastID = c.Query("last_event_id")
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	fmt.Fprint(c.Writer, "retry: 2000\n\n")
+
+	sent, _ := strconv.ParseUint(lastID, 10, 64)
+	send := func(e events.Event) {
+		data, _ := json.Marshal(e)
+		fmt.Fprintf(c.Writer, "id: %s\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+		sent, _ = strconv.ParseUint(e.ID, 10, 64)
+	}
+	replay := func() error {
+		page := events.Page{Next: strconv.FormatUint(sent, 10)}
+		for page.Next != "" {
+			next := q
+			next.After = page.Next
+			next.Limit = events.MaxLimit
+			var err error
+			if page, err = events.Default.List(next); err != nil {
+				return err
+			}
+			for _, e := range page.Events {
+				send(e)
+			}
+			c.Writer.Flush()
+		}
+		return nil
+	}
+
+	// The backlog is replayed before subscribing so a long replay can't
+	// overflow the subscription. Whatever was written meanwhile is replayed
+	// once more after subscribing and skipped when it arrives live.
+	if lastID != "" {
+		if err := replay(); err != nil {
+			return
+		}
+	}
+	broker := events.Default.Broker
+	sub := broker.Subscribe()
+	defer broker.Unsubscribe(sub)
+	if lastID != "" {
+		if err := replay(); err != nil {
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"message": "Error reading from the file"})
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": ping\n\n")
+			c.Writer.Flush()
+		case e, ok := <-sub:
+			if !ok {
+				return
+			}
+			if id, _ := strconv.ParseUint(e.ID, 10, 64); id <= sent || !q.Matches(e) {
+				continue
+			}
+			send(e)
+			c.Writer.Flush()
+		}
+	}
+}
+
+func eventsQuery(c *gin.Context) (events.Query, error) {
+	q := events.Query{
+		Source:   c.Query("source"),
+		Resource: c.Query("resource"),
+		After:    c.Query("after"),
+	}
+	for _, t := range strings.Split(c.Query("type"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			q.Types = append(q.Types, events.Type(strings.ToUpper(t)))
+		}
+	}
+
+	for name, dst := range map[string]*time.Time{"since": &q.Since, "until": &q.Until} {
+		if v := c.Query(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return q, fmt.Errorf("%s must be RFC3339", name)
+			}
+			*dst = t
+		}
+	}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return q, fmt.Errorf("limit must be an integer")
+		}
+		q.Limit = limit
+	}
+	return q, nil
+}
+
+// HistoryHandler lists recorded runs, or returns a cost series when
+// resource, provider or group_by is given.
+func HistoryHandler(c *gin.Context) {
+	q := history.Query{
+		Kind:   history.Kind(c.Query("kind")),
+		Remote: c.Query("repo"),
+	}
+
+	if v := c.Query("since"); v != "" {
+		since, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(400, gin.H{"error": "since must be RFC3339"})
+			return
+		}
+		q.Since = since
+	}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(400, gin.H{"error": "limit must be an integer"})
+			return
+		}
+		q.Limit = limit
+	}
+
+	groupBy, key := c.Query("group_by"), ""
+	switch {
+	case c.Query("resource") != "":
+		groupBy, key = history.GroupByResource, c.Query("resource")
+	case c.Query("provider") != "":
+		groupBy, key = history.GroupByProvider, c.Query("provider")
+	}
+
+	if groupBy != "" {
+		var series []history.CostPoint
+		err := withHistory(func(store *history.Store) (err error) {
+			series, err = store.CostSeries(q, groupBy, key)
+			return err
+		})
+		if err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(200, gin.H{"group_by": groupBy, "points": series})
+		return
+	}
+
+	var entries []history.Entry
+	err := withHistory(func(store *history.Store) (err error) {
+		entries, err = store.List(q)
+		return err
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"entries": entries})
+}
+
+// ForecastHandler projects cost from history. Query params: months (1-3,
+// default 3) and budget.
+func ForecastHandler(c *gin.Context) {
+	months, err := strconv.Atoi(c.DefaultQuery("months", "3"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "months must be an integer"})
+		return
+	}
+	budget, err := strconv.ParseFloat(c.DefaultQuery("budget", "0"), 64)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "budget must be a number"})
 		return
 	}
 
-	c.Data(200, "application/json", jsonBytes)
+	var resp types.ForecastResponse
+	err = withHistory(func(store *history.Store) (err error) {
+		resp, err = forecast.Project(store, history.Query{}, months, budget)
+		return err
+	})
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, resp)
+}
+
+func HistoryEntryHandler(c *gin.Context) {
+	var entry history.Entry
+	err := withHistory(func(store *history.Store) (err error) {
+		entry, err = store.Get(c.Param("id"))
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, entry)
 }
 
 func main() {
+	if path := os.Getenv("COSTGUARD_PRICING_FILE"); path != "" {
+		catalog, err := pricing.Load(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		pricing.Default = catalog
+	}
+
+	historyPath = os.Getenv("COSTGUARD_HISTORY_DB")
+	if historyPath == "" {
+		historyPath = history.DefaultPath
+	}
+	// Fail fast on an unusable path rather than on the first request.
+	if err := withHistory(func(*history.Store) error { return nil }); err != nil {
+		log.Fatal(err)
+	}
+
+	eventsPath := os.Getenv("COSTGUARD_EVENTS_LOG")
+	if eventsPath == "" {
+		eventsPath = events.DefaultPath
+	}
+	eventLog, err := events.Open(eventsPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	events.Default = eventLog
+
 	router := gin.Default()
 	router.GET("/health", HealthHandler)
 	router.POST("/v1/scan", ScanHandler)
+	router.POST("/v1/scan/diff", ScanDiffHandler)
 	router.POST("/v1/fixplans", FixPlansHandler)
+	router.POST("/v1/decisions", DecisionsHandler)
+	router.POST("/v1/pr", PRHandler)
+	router.POST("/v1/simulate", SimulateHandler)
+	router.POST("/v1/impact", ImpactHandler)
+	router.POST("/v1/report", ReportHandler)
 	router.GET("/v1/events", EventsHandler)
+	router.GET("/v1/events/stream", EventsStreamHandler)
+	router.GET("/v1/history", HistoryHandler)
+	router.GET("/v1/history/:id", HistoryEntryHandler)
+	router.GET("/v1/forecast", ForecastHandler)
 	router.Run()
 }
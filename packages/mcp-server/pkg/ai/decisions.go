@@ -4,38 +4,26 @@ import (
 	"fmt"
 	"sort"
 
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/events"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/provider/kubernetes"
 	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
 )
 
 func MakeDecisions(plan types.FixPlanResponse) types.AIDecisionSummary {
 	decisions := []types.AIDecision{}
 
-	actionSavings := make(map[int]float64)
-	totalSavings := plan.TotalSavings
-	numActions := len(plan.Actions)
-
-	avgSavings := totalSavings / float64(numActions)
-
-	for i, action := range plan.Actions {
-		savings := avgSavings
-		if action.EstimatedSavingsUSD > 0 {
-			savings = action.EstimatedSavingsUSD
-		}
-		actionSavings[i] = savings
-	}
-
-	type actionWithIndex struct {
-		index   int
+	type actionWithSavings struct {
 		action  types.FixAction
 		savings float64
 	}
 
-	actionsSorted := make([]actionWithIndex, 0, len(plan.Actions))
-	for i, action := range plan.Actions {
-		actionsSorted = append(actionsSorted, actionWithIndex{
-			index:   i,
+	// Each action is judged on its own estimate; actions that save nothing
+	// fall through to "skip" below.
+	actionsSorted := make([]actionWithSavings, 0, len(plan.Actions))
+	for _, action := range plan.Actions {
+		actionsSorted = append(actionsSorted, actionWithSavings{
 			action:  action,
-			savings: actionSavings[i],
+			savings: action.EstimatedSavingsUSD,
 		})
 	}
 
@@ -64,7 +52,24 @@ func MakeDecisions(plan types.FixPlanResponse) types.AIDecisionSummary {
 			}
 		}
 
-		if changePercent > 50 {
+		if action.RequiresApproval {
+			riskLevel = action.RiskLevel
+			if riskLevel == "" {
+				riskLevel = "high"
+			}
+			decision = "defer"
+			actionsDeferred++
+		} else if action.RiskLevel == "medium" || action.RiskLevel == "high" {
+			riskLevel = action.RiskLevel
+			decision = "defer"
+			actionsDeferred++
+		} else if action.RiskCategory == kubernetes.RiskCategoryPlacement {
+			// Moving pods between nodes needs someone to check affinity
+			// and capacity first.
+			riskLevel = "medium"
+			decision = "defer"
+			actionsDeferred++
+		} else if changePercent > 50 {
 			riskLevel = "high"
 			decision = "defer"
 			actionsDeferred++
@@ -89,7 +94,7 @@ func MakeDecisions(plan types.FixPlanResponse) types.AIDecisionSummary {
 		)
 
 		decisions = append(decisions, types.AIDecision{
-			ActionID:            fmt.Sprintf("action-%d", item.index),
+			ActionID:            action.ID,
 			Decision:            decision,
 			Rationale:           rationale,
 			Priority:            priority,
@@ -98,6 +103,16 @@ func MakeDecisions(plan types.FixPlanResponse) types.AIDecisionSummary {
 		})
 	}
 
+	for i, d := range decisions {
+		events.Emit(events.Event{
+			Type:     events.DecisionMade,
+			Source:   "ai",
+			Resource: actionsSorted[i].action.Resource,
+			Message:  fmt.Sprintf("%s %s (%s)", d.Decision, actionsSorted[i].action.Intent, d.ActionID),
+			Payload:  d,
+		})
+	}
+
 	summary := fmt.Sprintf(
 		"AI Decision Summary: %d actions to apply (savings: $%.2f/month), %d deferred, %d skipped",
 		actionsToApply, totalSavingsToApply, actionsDeferred, actionsSkipped,
@@ -0,0 +1,126 @@
+package allocation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+// Allocate groups cost by the comma-separated label keys in groupBy, e.g.
+// "team" or "team,env". Keys match case-insensitively so Kubernetes labels
+// and cloud tags like "Team" land in the same group. Resources missing any
+// key are unlabelled and handled as the unlabelled mode says.
+func Allocate(agg []types.AggregatedMetrics, groupBy, unlabelled string) (types.AllocationReport, error) {
+	keys := []string{}
+	for _, k := range strings.Split(groupBy, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return types.AllocationReport{}, fmt.Errorf("group by needs at least one label key")
+	}
+
+	if unlabelled == "" {
+		unlabelled = types.UnlabelledKeep
+	}
+	if unlabelled != types.UnlabelledKeep && unlabelled != types.UnlabelledDistribute {
+		return types.AllocationReport{}, fmt.Errorf("unknown unlabelled mode %q: use %s or %s",
+			unlabelled, types.UnlabelledKeep, types.UnlabelledDistribute)
+	}
+
+	report := types.AllocationReport{GroupBy: keys, Unlabelled: unlabelled}
+	groups := map[string]*types.AllocationGroup{}
+
+	for _, a := range agg {
+		// Shared cluster workloads are already charged to the others
+		// through their overhead.
+		overhead := 0.0
+		if a.Overhead != nil {
+			if a.Overhead.Shared {
+				continue
+			}
+			overhead = a.Overhead.SharedCostUSD + a.Overhead.IdleCostUSD
+		}
+		cost := a.CostCurrentUSD + overhead
+
+		value, ok := groupValue(a.Labels, keys)
+		if !ok {
+			value = types.Unlabelled
+			report.UnlabelledCostUSD += cost
+		}
+
+		g := groups[value]
+		if g == nil {
+			g = &types.AllocationGroup{Value: value, Resources: []string{}}
+			groups[value] = g
+		}
+		g.Resources = append(g.Resources, a.Resource)
+		g.CurrentCostUSD += a.CostCurrentUSD
+		g.OverheadCostUSD += overhead
+		g.OptimalCostUSD += a.CostOptimalUSD
+		g.SavingsUSD += a.CostSavingsUSD
+		report.TotalCostUSD += cost
+	}
+
+	// Distributing needs somewhere to put the spend; with nothing labelled
+	// the unlabelled group is kept as is.
+	labelledCost := report.TotalCostUSD - report.UnlabelledCostUSD
+	if unlabelled == types.UnlabelledDistribute && groups[types.Unlabelled] != nil && labelledCost > 0 {
+		for value, g := range groups {
+			if value != types.Unlabelled {
+				g.SharedCostUSD = report.UnlabelledCostUSD * (g.CurrentCostUSD + g.OverheadCostUSD) / labelledCost
+			}
+		}
+		delete(groups, types.Unlabelled)
+	}
+
+	for _, g := range groups {
+		g.TotalCostUSD = g.CurrentCostUSD + g.OverheadCostUSD + g.SharedCostUSD
+		if report.TotalCostUSD > 0 {
+			g.SharePercent = g.TotalCostUSD / report.TotalCostUSD * 100
+		}
+		sort.Strings(g.Resources)
+		report.Groups = append(report.Groups, *g)
+	}
+
+	// Largest first, with the unlabelled group always last.
+	sort.Slice(report.Groups, func(i, j int) bool {
+		gi, gj := report.Groups[i], report.Groups[j]
+		if (gi.Value == types.Unlabelled) != (gj.Value == types.Unlabelled) {
+			return gj.Value == types.Unlabelled
+		}
+		if gi.TotalCostUSD != gj.TotalCostUSD {
+			return gi.TotalCostUSD > gj.TotalCostUSD
+		}
+		return gi.Value < gj.Value
+	})
+
+	return report, nil
+}
+
+func groupValue(labels map[string]string, keys []string) (string, bool) {
+	values := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v, ok := label(labels, k)
+		if !ok || v == "" {
+			return "", false
+		}
+		values = append(values, v)
+	}
+	return strings.Join(values, "/"), true
+}
+
+func label(labels map[string]string, key string) (string, bool) {
+	if v, ok := labels[key]; ok {
+		return v, true
+	}
+	for k, v := range labels {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return "", false
+}
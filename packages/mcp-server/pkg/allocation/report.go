@@ -0,0 +1,60 @@
+package allocation
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+// CSV renders one row per group, suitable for a chargeback spreadsheet.
+func CSV(r types.AllocationReport) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	money := func(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
+
+	rows := [][]string{{
+		strings.Join(r.GroupBy, "/"), "resources", "current_cost_usd", "overhead_cost_usd", "shared_cost_usd",
+		"total_cost_usd", "optimal_cost_usd", "savings_usd", "share_percent",
+	}}
+	for _, g := range r.Groups {
+		rows = append(rows, []string{
+			g.Value, strconv.Itoa(len(g.Resources)), money(g.CurrentCostUSD), money(g.OverheadCostUSD), money(g.SharedCostUSD),
+			money(g.TotalCostUSD), money(g.OptimalCostUSD), money(g.SavingsUSD), money(g.SharePercent),
+		})
+	}
+
+	if err := w.WriteAll(rows); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func Markdown(r types.AllocationReport) string {
+	var b strings.Builder
+
+	by := strings.Join(r.GroupBy, "/")
+	b.WriteString(fmt.Sprintf("## CostGuard cost allocation by %s\n\n", by))
+	b.WriteString(fmt.Sprintf("Total: $%.2f / month", r.TotalCostUSD))
+	if r.UnlabelledCostUSD > 0 {
+		verb := "reported separately"
+		if r.Unlabelled == types.UnlabelledDistribute {
+			verb = "distributed by cost share"
+		}
+		b.WriteString(fmt.Sprintf(", of which $%.2f unlabelled (%s)", r.UnlabelledCostUSD, verb))
+	}
+	b.WriteString(".\n\n")
+
+	b.WriteString(fmt.Sprintf("| %s | Resources | Cost | Overhead | Unlabelled share | Total | Share | Savings |\n", by))
+	b.WriteString("|---|---:|---:|---:|---:|---:|---:|---:|\n")
+	for _, g := range r.Groups {
+		b.WriteString(fmt.Sprintf("| %s | %d | $%.2f | $%.2f | $%.2f | $%.2f | %.1f%% | $%.2f |\n",
+			g.Value, len(g.Resources), g.CurrentCostUSD, g.OverheadCostUSD, g.SharedCostUSD, g.TotalCostUSD, g.SharePercent, g.SavingsUSD))
+	}
+
+	return b.String()
+}
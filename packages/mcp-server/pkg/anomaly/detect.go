@@ -0,0 +1,281 @@
+package anomaly
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+const (
+	// MinPoints is the shortest series checked; fewer samples give no
+	// baseline to compare against.
+	MinPoints = 12
+
+	// Robust z-scores at or above these mark a warning or critical anomaly.
+	warnScore     = 5
+	criticalScore = 10
+
+	// A series grows like a leak when it rises by this share of its median
+	// over the window, and does so steadily (Kendall's tau).
+	warnGrowth     = 0.2
+	criticalGrowth = 0.5
+	minTau         = 0.6
+
+	// Theil-Sen and Kendall's tau look at all pairs, so long series are
+	// thinned to this many points first.
+	maxTrendPoints = 200
+
+	// madScale makes the MAD a consistent estimator of the standard deviation
+	// for normal data.
+	madScale = 1.4826
+	// minRelScale floors the spread at a share of the larger of the two
+	// levels compared, so flat series do not turn tiny wobbles into huge
+	// scores and a rise from a zero baseline still gets a finite score.
+	minRelScale = 0.05
+)
+
+// Detect checks every watched metric of one resource and returns at most one
+// anomaly per metric, ordered by metric name.
+func Detect(provider types.Provider, samples []types.MetricCollection) []types.Anomaly {
+	series := Series(provider, samples)
+
+	metrics := make([]string, 0, len(series))
+	for m := range series {
+		metrics = append(metrics, m)
+	}
+	sort.Strings(metrics)
+
+	var out []types.Anomaly
+	for _, m := range metrics {
+		if a, ok := detectSeries(m, series[m]); ok {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+func detectSeries(metric string, pts []Point) (types.Anomaly, bool) {
+	if len(pts) < MinPoints {
+		return types.Anomaly{}, false
+	}
+
+	if memoryMetrics[metric] {
+		if a, ok := growthTrend(metric, pts); ok {
+			return a, true
+		}
+	}
+	if a, ok := tailShift(metric, pts); ok {
+		return a, true
+	}
+	return changepoint(metric, pts)
+}
+
+// tailShift compares the latest tenth of the series against everything
+// before it. This catches spikes and drops that are still happening.
+func tailShift(metric string, pts []Point) (types.Anomaly, bool) {
+	values := valuesOf(pts)
+	n := len(values)
+	w := max(1, n/10)
+
+	base := values[:n-w]
+	baseline := median(base)
+	current := median(values[n-w:])
+	scale := math.Max(spread(base, baseline), minRelScale*math.Abs(current))
+	if scale == 0 {
+		return types.Anomaly{}, false
+	}
+
+	z := (current - baseline) / scale
+	if math.Abs(z) < warnScore {
+		return types.Anomaly{}, false
+	}
+
+	kind, verb := types.AnomalySpike, "spiked"
+	if z < 0 {
+		kind, verb = types.AnomalyDrop, "dropped"
+	}
+	return types.Anomaly{
+		Metric:      metric,
+		Kind:        kind,
+		Severity:    severity(math.Abs(z), criticalScore),
+		StartedAt:   pts[n-w].Timestamp,
+		Ongoing:     true,
+		Baseline:    baseline,
+		Current:     current,
+		Score:       z,
+		Description: fmt.Sprintf("%s %s from a median of %.2f to %.2f", metric, verb, baseline, current),
+	}, true
+}
+
+// changepoint finds the single split that best separates the series into two
+// levels by squared error, then scores the gap between the segment medians
+// against the pooled MAD of both segments.
+func changepoint(metric string, pts []Point) (types.Anomaly, bool) {
+	values := valuesOf(pts)
+	n := len(values)
+	minSeg := max(3, n/20)
+
+	sum := make([]float64, n+1)
+	sumSq := make([]float64, n+1)
+	for i, v := range values {
+		sum[i+1] = sum[i] + v
+		sumSq[i+1] = sumSq[i] + v*v
+	}
+	sse := func(a, b int) float64 {
+		s := sum[b] - sum[a]
+		return sumSq[b] - sumSq[a] - s*s/float64(b-a)
+	}
+
+	split, best := 0, math.Inf(1)
+	for k := minSeg; k <= n-minSeg; k++ {
+		if cost := sse(0, k) + sse(k, n); cost < best {
+			split, best = k, cost
+		}
+	}
+	if split == 0 {
+		return types.Anomaly{}, false
+	}
+
+	before, after := values[:split], values[split:]
+	baseline, current := median(before), median(after)
+
+	resid := make([]float64, 0, n)
+	for _, v := range before {
+		resid = append(resid, v-baseline)
+	}
+	for _, v := range after {
+		resid = append(resid, v-current)
+	}
+	scale := math.Max(madScale*mad(resid, 0), minRelScale*math.Max(math.Abs(baseline), math.Abs(current)))
+	if scale == 0 {
+		return types.Anomaly{}, false
+	}
+
+	z := (current - baseline) / scale
+	if math.Abs(z) < warnScore {
+		return types.Anomaly{}, false
+	}
+
+	dir := "up"
+	if z < 0 {
+		dir = "down"
+	}
+	// A shift in the last quarter of the window still dominates neither
+	// the old nor the new level, so usage stats are not trustworthy yet.
+	return types.Anomaly{
+		Metric:      metric,
+		Kind:        types.AnomalyLevelShift,
+		Severity:    severity(math.Abs(z), criticalScore),
+		StartedAt:   pts[split].Timestamp,
+		Ongoing:     n-split <= n/4,
+		Baseline:    baseline,
+		Current:     current,
+		Score:       z,
+		Description: fmt.Sprintf("%s shifted %s from a median of %.2f to %.2f", metric, dir, baseline, current),
+	}, true
+}
+
+// growthTrend flags steady growth using the Theil-Sen slope, which ignores
+// outliers, and Kendall's tau to require the rise to be monotonic rather
+// than a single jump.
+func growthTrend(metric string, pts []Point) (types.Anomaly, bool) {
+	n := len(pts)
+	sample := pts
+	if n > maxTrendPoints {
+		sample = make([]Point, 0, maxTrendPoints)
+		for i := 0; i < maxTrendPoints; i++ {
+			sample = append(sample, pts[i*(n-1)/(maxTrendPoints-1)])
+		}
+	}
+
+	slopes := []float64{}
+	concordant := 0
+	for i := range sample {
+		for j := i + 1; j < len(sample); j++ {
+			dt := float64(sample[j].Timestamp - sample[i].Timestamp)
+			dv := sample[j].Value - sample[i].Value
+			if dt == 0 {
+				continue
+			}
+			slopes = append(slopes, dv/dt)
+			switch {
+			case dv > 0:
+				concordant++
+			case dv < 0:
+				concordant--
+			}
+		}
+	}
+	if len(slopes) == 0 {
+		return types.Anomaly{}, false
+	}
+
+	tau := float64(concordant) / float64(len(slopes))
+	level := math.Abs(median(valuesOf(pts)))
+	if tau < minTau || level == 0 {
+		return types.Anomaly{}, false
+	}
+
+	growth := median(slopes) * float64(pts[n-1].Timestamp-pts[0].Timestamp) / level
+	if growth < warnGrowth {
+		return types.Anomaly{}, false
+	}
+
+	q := max(1, n/4)
+	baseline, current := median(valuesOf(pts[:q])), median(valuesOf(pts[n-q:]))
+	return types.Anomaly{
+		Metric:      metric,
+		Kind:        types.AnomalyGrowthTrend,
+		Severity:    severity(growth, criticalGrowth),
+		StartedAt:   pts[0].Timestamp,
+		Ongoing:     true,
+		Baseline:    baseline,
+		Current:     current,
+		Score:       growth,
+		Description: fmt.Sprintf("%s grew steadily by %.0f%% over the window (%.2f → %.2f), possible leak", metric, growth*100, baseline, current),
+	}, true
+}
+
+func severity(score, critical float64) string {
+	if score >= critical {
+		return types.SeverityCritical
+	}
+	return types.SeverityWarning
+}
+
+func valuesOf(pts []Point) []float64 {
+	out := make([]float64, len(pts))
+	for i, p := range pts {
+		out[i] = p.Value
+	}
+	return out
+}
+
+func median(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	s := append([]float64(nil), xs...)
+	sort.Float64s(s)
+	mid := len(s) / 2
+	if len(s)%2 == 0 {
+		return (s[mid-1] + s[mid]) / 2
+	}
+	return s[mid]
+}
+
+func mad(xs []float64, center float64) float64 {
+	dev := make([]float64, len(xs))
+	for i, x := range xs {
+		dev[i] = math.Abs(x - center)
+	}
+	return median(dev)
+}
+
+// spread is the MAD-based standard deviation of xs, floored relative to
+// the median.
+func spread(xs []float64, med float64) float64 {
+	return math.Max(madScale*mad(xs, med), minRelScale*math.Abs(med))
+}
@@ -0,0 +1,179 @@
+package anomaly
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+// series builds n hourly points from f, with a small deterministic wobble
+// so the MAD is not zero.
+func series(n int, wobble float64, f func(i int) float64) []Point {
+	pts := make([]Point, n)
+	for i := range pts {
+		pts[i] = Point{
+			Timestamp: int64(1700000000 + i*3600),
+			Value:     f(i) + wobble*float64((i*7)%5-2),
+		}
+	}
+	return pts
+}
+
+// step is before until index at and after from there on.
+func step(at int, before, after float64) func(int) float64 {
+	return func(i int) float64 {
+		if i >= at {
+			return after
+		}
+		return before
+	}
+}
+
+func TestDetectSeries(t *testing.T) {
+	tests := []struct {
+		name     string
+		metric   string
+		pts      []Point
+		want     string
+		positive bool
+		ongoing  bool
+	}{
+		{
+			name:   "flat",
+			metric: "cpu_milli",
+			pts:    series(100, 1, func(int) float64 { return 100 }),
+		},
+		{
+			name:   "too short",
+			metric: "cpu_milli",
+			pts:    series(MinPoints-1, 0, func(i int) float64 { return float64(i * i * 100) }),
+		},
+		{
+			name:     "tail spike",
+			metric:   "cpu_milli",
+			pts:      series(100, 1, step(90, 100, 500)),
+			want:     types.AnomalySpike,
+			positive: true,
+			ongoing:  true,
+		},
+		{
+			name:     "spike from zero baseline",
+			metric:   "request_count",
+			pts:      series(100, 0, step(90, 0, 5)),
+			want:     types.AnomalySpike,
+			positive: true,
+			ongoing:  true,
+		},
+		{
+			name:    "tail drop",
+			metric:  "cpu_milli",
+			pts:     series(100, 1, step(90, 100, 5)),
+			want:    types.AnomalyDrop,
+			ongoing: true,
+		},
+		{
+			name:     "early level shift",
+			metric:   "cpu_milli",
+			pts:      series(100, 1, step(30, 100, 300)),
+			want:     types.AnomalyLevelShift,
+			positive: true,
+		},
+		{
+			name:     "recent level shift",
+			metric:   "cpu_milli",
+			pts:      series(100, 1, step(80, 100, 300)),
+			want:     types.AnomalySpike,
+			positive: true,
+			ongoing:  true,
+		},
+		{
+			name:     "memory leak",
+			metric:   "memory_gb",
+			pts:      series(100, 0.001, func(i int) float64 { return 1 + float64(i)/99 }),
+			want:     types.AnomalyGrowthTrend,
+			positive: true,
+			ongoing:  true,
+		},
+		{
+			name:   "steady cpu growth is not a leak",
+			metric: "cpu_milli",
+			pts:    series(100, 0.001, func(i int) float64 { return 1 + float64(i)/99 }),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, ok := detectSeries(tt.metric, tt.pts)
+			if tt.want == "" {
+				if ok {
+					t.Fatalf("unexpected anomaly: %+v", a)
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("no anomaly, want %s", tt.want)
+			}
+			if a.Kind != tt.want {
+				t.Errorf("Kind = %s, want %s (%s)", a.Kind, tt.want, a.Description)
+			}
+			if (a.Score > 0) != tt.positive {
+				t.Errorf("Score = %.2f, want positive=%v", a.Score, tt.positive)
+			}
+			if a.Ongoing != tt.ongoing {
+				t.Errorf("Ongoing = %v, want %v", a.Ongoing, tt.ongoing)
+			}
+			if math.IsInf(a.Score, 0) || math.IsNaN(a.Score) {
+				t.Errorf("Score = %v, want finite", a.Score)
+			}
+		})
+	}
+}
+
+func TestGrowthTrendIgnoresOutliers(t *testing.T) {
+	pts := series(100, 0, func(i int) float64 { return 1 + float64(i)/99 })
+	pts[10].Value = 50
+	pts[60].Value = 0
+
+	a, ok := growthTrend("memory_gb", pts)
+	if !ok {
+		t.Fatal("no growth trend detected")
+	}
+	// Theil-Sen recovers the underlying 100% rise relative to the median
+	// level of 1.5.
+	if want := 1 / 1.5; math.Abs(a.Score-want) > 0.01 {
+		t.Errorf("Score = %.3f, want %.3f", a.Score, want)
+	}
+	if a.Severity != types.SeverityCritical {
+		t.Errorf("Severity = %s, want %s", a.Severity, types.SeverityCritical)
+	}
+}
+
+func TestGrowthTrendRejectsSingleJump(t *testing.T) {
+	pts := series(100, 0, step(50, 1, 2))
+	if a, ok := growthTrend("memory_gb", pts); ok {
+		t.Errorf("single step reported as growth: %+v", a)
+	}
+}
+
+func TestDetectOrdersByMetric(t *testing.T) {
+	samples := []types.MetricCollection{}
+	for i := 0; i < 100; i++ {
+		v := 100.0
+		if i >= 90 {
+			v = 1000
+		}
+		samples = append(samples, types.MetricCollection{
+			TimeStamp: int64(1700000000 + i*3600),
+			Metrics: types.ResourceMetrics{K8sResourceMetrics: types.K8sResourceMetrics{
+				CpuMilli: v,
+				MemoryGB: v / 100,
+			}},
+		})
+	}
+
+	got := Detect(types.ProviderKubernetes, samples)
+	if len(got) != 2 || got[0].Metric != "cpu_milli" || got[1].Metric != "memory_gb" {
+		t.Fatalf("Detect = %+v, want cpu_milli then memory_gb", got)
+	}
+}
@@ -0,0 +1,91 @@
+package anomaly
+
+import (
+	"sort"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+type Point struct {
+	Timestamp int64
+	Value     float64
+}
+
+type extractor struct {
+	metric string
+	value  func(m types.ResourceMetrics) (float64, bool)
+}
+
+func always(f func(m types.ResourceMetrics) float64) func(m types.ResourceMetrics) (float64, bool) {
+	return func(m types.ResourceMetrics) (float64, bool) { return f(m), true }
+}
+
+// extractors lists the usage metrics watched per provider, named like the
+// aggregated metrics. Storage, buckets and flows are cumulative or sparse and
+// are not watched.
+var extractors = map[types.Provider][]extractor{
+	types.ProviderKubernetes: {
+		{"cpu_milli", always(func(m types.ResourceMetrics) float64 { return m.K8sResourceMetrics.CpuMilli })},
+		{"memory_gb", always(func(m types.ResourceMetrics) float64 { return m.K8sResourceMetrics.MemoryGB })},
+		{"request_count", func(m types.ResourceMetrics) (float64, bool) {
+			if m.K8sResourceMetrics.RequestCount == nil {
+				return 0, false
+			}
+			return *m.K8sResourceMetrics.RequestCount, true
+		}},
+	},
+	types.ProviderGCPCloudRun: {
+		{"requests_per_second", always(func(m types.ResourceMetrics) float64 { return m.CloudRunResourceMetrics.RequestsPerSecond })},
+		{"cpu_utilization", always(func(m types.ResourceMetrics) float64 { return m.CloudRunResourceMetrics.CpuUtilization })},
+		{"memory_utilization", always(func(m types.ResourceMetrics) float64 { return m.CloudRunResourceMetrics.MemoryUtilization })},
+	},
+	types.ProviderAzureContainerApps: {
+		{"cpu_usage", always(func(m types.ResourceMetrics) float64 { return m.ContainerAppsResourceMetrics.CpuUsage })},
+		{"memory_usage_gb", always(func(m types.ResourceMetrics) float64 { return m.ContainerAppsResourceMetrics.MemoryUsageGB })},
+	},
+	types.ProviderAWSECSFargate: {
+		{"cpu_percent", always(func(m types.ResourceMetrics) float64 { return m.ECSTaskResourceMetrics.CpuUtilization })},
+		{"memory_percent", always(func(m types.ResourceMetrics) float64 { return m.ECSTaskResourceMetrics.MemoryUtilization })},
+	},
+	types.ProviderAWSRDS: {
+		{"cpu_percent", always(func(m types.ResourceMetrics) float64 { return m.RDSResourceMetrics.CpuPercent })},
+		{"connections", always(func(m types.ResourceMetrics) float64 { return m.RDSResourceMetrics.Connections })},
+		{"iops", always(func(m types.ResourceMetrics) float64 {
+			return m.RDSResourceMetrics.ReadIOPS + m.RDSResourceMetrics.WriteIOPS
+		})},
+	},
+	types.ProviderGCPGCE: {
+		{"cpu_percent", always(func(m types.ResourceMetrics) float64 { return m.VMResourceMetrics.CpuPercent })},
+		{"memory_percent", always(func(m types.ResourceMetrics) float64 { return m.VMResourceMetrics.MemoryPercent })},
+	},
+	types.ProviderAzureVM: {
+		{"cpu_percent", always(func(m types.ResourceMetrics) float64 { return m.VMResourceMetrics.CpuPercent })},
+		{"memory_percent", always(func(m types.ResourceMetrics) float64 { return m.VMResourceMetrics.MemoryPercent })},
+	},
+}
+
+// memoryMetrics are checked for steady growth, which is how a leak shows up.
+var memoryMetrics = map[string]bool{
+	"memory_gb":          true,
+	"memory_utilization": true,
+	"memory_usage_gb":    true,
+	"memory_percent":     true,
+}
+
+// Series splits the samples of one resource into a time-ordered series per
+// watched metric.
+func Series(provider types.Provider, samples []types.MetricCollection) map[string][]Point {
+	out := map[string][]Point{}
+	for _, s := range samples {
+		for _, e := range extractors[provider] {
+			if v, ok := e.value(s.Metrics); ok {
+				out[e.metric] = append(out[e.metric], Point{Timestamp: s.TimeStamp, Value: v})
+			}
+		}
+	}
+
+	for _, pts := range out {
+		sort.SliceStable(pts, func(i, j int) bool { return pts[i].Timestamp < pts[j].Timestamp })
+	}
+	return out
+}
@@ -0,0 +1,116 @@
+package check
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/diff"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+// Evaluate applies t to a scan. Growth and new-resource rules only run when
+// a baseline scan is given.
+func Evaluate(resp types.ScanResponse, baseline *types.ScanResponse, t types.CheckThresholds) types.CheckResult {
+	res := types.CheckResult{
+		TotalCostUSD: resp.Summary.TotalCurrentCostUSD,
+		Thresholds:   t,
+		Violations:   []types.CheckViolation{},
+	}
+
+	if t.BudgetTarget > 0 && res.TotalCostUSD > t.BudgetTarget {
+		msg := fmt.Sprintf("Total cost $%.2f/month exceeds the budget of $%.2f", res.TotalCostUSD, t.BudgetTarget)
+		if resp.Summary.TotalOptimalCostUSD <= t.BudgetTarget {
+			msg += fmt.Sprintf(" (applying recommended fixes would bring it to $%.2f)", resp.Summary.TotalOptimalCostUSD)
+		}
+		res.Violations = append(res.Violations, types.CheckViolation{
+			Rule:    types.CheckRuleBudget,
+			Actual:  res.TotalCostUSD,
+			Limit:   t.BudgetTarget,
+			Message: msg,
+		})
+	}
+
+	if t.MaxWastePercent > 0 {
+		for _, r := range resp.Resources {
+			if r.Costs.WastePercentage <= t.MaxWastePercent {
+				continue
+			}
+			res.Violations = append(res.Violations, types.CheckViolation{
+				Rule:     types.CheckRuleWaste,
+				Provider: r.Provider,
+				Resource: r.Resource,
+				Actual:   r.Costs.WastePercentage,
+				Limit:    t.MaxWastePercent,
+				Message: fmt.Sprintf("%s wastes %.1f%% of its cost ($%.2f/month), above the %.0f%% limit",
+					r.Resource, r.Costs.WastePercentage, r.Costs.PotentialSavingsUSD, t.MaxWastePercent),
+			})
+		}
+	}
+
+	if baseline != nil {
+		res.BaselineCostUSD = baseline.Summary.TotalCurrentCostUSD
+		if res.BaselineCostUSD > 0 {
+			res.GrowthPercent = (res.TotalCostUSD - res.BaselineCostUSD) / res.BaselineCostUSD * 100
+		}
+
+		if t.MaxGrowthPercent > 0 && res.GrowthPercent > t.MaxGrowthPercent {
+			res.Violations = append(res.Violations, types.CheckViolation{
+				Rule:   types.CheckRuleGrowth,
+				Actual: res.GrowthPercent,
+				Limit:  t.MaxGrowthPercent,
+				Message: fmt.Sprintf("Total cost grew %.1f%% ($%.2f → $%.2f/month), above the %.0f%% limit",
+					res.GrowthPercent, res.BaselineCostUSD, res.TotalCostUSD, t.MaxGrowthPercent),
+			})
+		}
+
+		if t.MaxNewResourceCostUSD > 0 {
+			for _, c := range diff.Compare(*baseline, resp, nil).Resources {
+				if c.Status != types.ChangeAdded || c.HeadCostUSD <= t.MaxNewResourceCostUSD {
+					continue
+				}
+				res.Violations = append(res.Violations, types.CheckViolation{
+					Rule:     types.CheckRuleNewResource,
+					Provider: c.Provider,
+					Resource: c.Resource,
+					Actual:   c.HeadCostUSD,
+					Limit:    t.MaxNewResourceCostUSD,
+					Message: fmt.Sprintf("New resource %s costs $%.2f/month, above the $%.2f limit",
+						c.Resource, c.HeadCostUSD, t.MaxNewResourceCostUSD),
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(res.Violations, func(i, j int) bool {
+		return res.Violations[i].Rule < res.Violations[j].Rule
+	})
+
+	res.Passed = len(res.Violations) == 0
+	return res
+}
+
+// GitHubAnnotations renders violations as GitHub Actions workflow commands.
+func GitHubAnnotations(res types.CheckResult) string {
+	var b strings.Builder
+	for _, v := range res.Violations {
+		title := "CostGuard " + v.Rule
+		if v.Resource != "" {
+			title += ": " + v.Resource
+		}
+		b.WriteString(fmt.Sprintf("::error title=%s::%s\n", escapeProperty(title), escapeData(v.Message)))
+	}
+	if res.Passed {
+		b.WriteString(fmt.Sprintf("::notice title=CostGuard::Cost check passed ($%.2f/month)\n", res.TotalCostUSD))
+	}
+	return b.String()
+}
+
+// escapeData and escapeProperty follow the workflow command encoding rules.
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
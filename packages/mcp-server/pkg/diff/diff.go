@@ -0,0 +1,286 @@
+package diff
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+const (
+	SourceHistory  = "history"
+	SourceInferred = "inferred"
+
+	// Cost and request changes below these are rounding noise.
+	costEpsilonUSD = 0.01
+	// A request counts as following a recommendation when it lands within
+	// this fraction of the recommended value.
+	recommendationTolerance = 0.1
+)
+
+// Compare explains how head differs from base. applied are fixes recorded
+// as applied between the two scans; fixes that are visible in head but were
+// not recorded are inferred from base's recommendations.
+func Compare(base, head types.ScanResponse, applied []types.FixAction) types.ScanDiff {
+	d := types.ScanDiff{
+		Summary: types.ScanDiffSummary{
+			BaseCostUSD:     base.Summary.TotalCurrentCostUSD,
+			HeadCostUSD:     head.Summary.TotalCurrentCostUSD,
+			CostDeltaUSD:    head.Summary.TotalCurrentCostUSD - base.Summary.TotalCurrentCostUSD,
+			BaseSavingsUSD:  base.Summary.TotalPotentialSavingsUSD,
+			HeadSavingsUSD:  head.Summary.TotalPotentialSavingsUSD,
+			SavingsDeltaUSD: head.Summary.TotalPotentialSavingsUSD - base.Summary.TotalPotentialSavingsUSD,
+		},
+		Resources:    []types.ResourceChange{},
+		FixesApplied: []types.AppliedFix{},
+	}
+
+	baseByKey := index(base)
+	headByKey := index(head)
+
+	keys := []string{}
+	for k := range baseByKey {
+		keys = append(keys, k)
+	}
+	for k := range headByKey {
+		if _, ok := baseByKey[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+
+	for _, k := range keys {
+		b, inBase := baseByKey[k]
+		h, inHead := headByKey[k]
+
+		c := types.ResourceChange{}
+		if inBase {
+			c.Provider, c.Resource = b.Provider, b.Resource
+			c.BaseCostUSD = b.Costs.CurrentCostUSD
+			c.BaseSavingsUSD = b.Costs.PotentialSavingsUSD
+			c.BaseWastePct = b.Costs.WastePercentage
+			c.BaseRequested = requested(b)
+			c.BaseUsageP95 = usageP95(b)
+			c.BaseInstanceType = instanceType(b)
+		}
+		if inHead {
+			c.Provider, c.Resource = h.Provider, h.Resource
+			c.HeadCostUSD = h.Costs.CurrentCostUSD
+			c.HeadSavingsUSD = h.Costs.PotentialSavingsUSD
+			c.HeadWastePct = h.Costs.WastePercentage
+			c.HeadRequested = requested(h)
+			c.HeadUsageP95 = usageP95(h)
+			c.HeadInstanceType = instanceType(h)
+		}
+		c.CostDeltaUSD = c.HeadCostUSD - c.BaseCostUSD
+		c.SavingsDeltaUSD = c.HeadSavingsUSD - c.BaseSavingsUSD
+
+		switch {
+		case !inBase:
+			c.Status = types.ChangeAdded
+			d.Summary.Added++
+		case !inHead:
+			c.Status = types.ChangeRemoved
+			d.Summary.Removed++
+		case math.Abs(c.CostDeltaUSD) >= costEpsilonUSD ||
+			math.Abs(c.SavingsDeltaUSD) >= costEpsilonUSD ||
+			c.BaseRequested != c.HeadRequested ||
+			c.BaseInstanceType != c.HeadInstanceType:
+			c.Status = types.ChangeChanged
+			d.Summary.Changed++
+		default:
+			c.Status = types.ChangeUnchanged
+		}
+
+		d.Resources = append(d.Resources, c)
+	}
+
+	sort.Slice(d.Resources, func(i, j int) bool {
+		ai, aj := math.Abs(d.Resources[i].CostDeltaUSD), math.Abs(d.Resources[j].CostDeltaUSD)
+		if ai != aj {
+			return ai > aj
+		}
+		return d.Resources[i].Resource < d.Resources[j].Resource
+	})
+
+	seen := map[string]bool{}
+	for _, a := range applied {
+		seen[a.Resource+"/"+a.Intent] = true
+		d.FixesApplied = append(d.FixesApplied, types.AppliedFix{
+			ActionID:            a.ID,
+			Resource:            a.Resource,
+			Intent:              a.Intent,
+			Description:         a.Description,
+			EstimatedSavingsUSD: a.EstimatedSavingsUSD,
+			Source:              SourceHistory,
+		})
+	}
+	for _, k := range keys {
+		b, inBase := baseByKey[k]
+		h, inHead := headByKey[k]
+		if !inBase || !inHead {
+			continue
+		}
+		for _, f := range inferFixes(b, h) {
+			if !seen[f.Resource+"/"+f.Intent] {
+				d.FixesApplied = append(d.FixesApplied, f)
+			}
+		}
+	}
+	sort.SliceStable(d.FixesApplied, func(i, j int) bool {
+		return d.FixesApplied[i].Resource < d.FixesApplied[j].Resource
+	})
+
+	return d
+}
+
+func index(resp types.ScanResponse) map[string]types.ScanResource {
+	out := map[string]types.ScanResource{}
+	for _, r := range resp.Resources {
+		out[string(r.Provider)+"/"+r.Resource] = r
+	}
+	return out
+}
+
+func requested(r types.ScanResource) types.Requests {
+	return types.Requests{CpuMilli: r.Requested.CpuMilli, MemoryGB: r.Requested.MemoryGB}
+}
+
+func usageP95(r types.ScanResource) types.Requests {
+	return types.Requests{CpuMilli: r.Usage["cpu_milli"].P95, MemoryGB: r.Usage["memory_gb"].P95}
+}
+
+func instanceType(r types.ScanResource) string {
+	if r.Machine == nil {
+		return ""
+	}
+	return r.Machine.InstanceType
+}
+
+func near(v, target float64) bool {
+	return target > 0 && math.Abs(v-target) <= target*recommendationTolerance
+}
+
+// inferFixes reports request and machine changes in head that follow what
+// base recommended.
+func inferFixes(b, h types.ScanResource) []types.AppliedFix {
+	out := []types.AppliedFix{}
+
+	if b.Requested.CpuMilli != h.Requested.CpuMilli && b.Requested.CpuMilli != b.Recommended.CpuMilli &&
+		near(h.Requested.CpuMilli, b.Recommended.CpuMilli) {
+		out = append(out, types.AppliedFix{
+			Resource:    b.Resource,
+			Intent:      "rightsize_cpu_request",
+			Description: fmt.Sprintf("CPU request %.0fm → %.0fm", b.Requested.CpuMilli, h.Requested.CpuMilli),
+			Source:      SourceInferred,
+		})
+	}
+
+	if b.Requested.MemoryGB != h.Requested.MemoryGB && b.Requested.MemoryGB != b.Recommended.MemoryGB &&
+		near(h.Requested.MemoryGB, b.Recommended.MemoryGB) {
+		out = append(out, types.AppliedFix{
+			Resource:    b.Resource,
+			Intent:      "rightsize_memory_request",
+			Description: fmt.Sprintf("Memory request %.2fGB → %.2fGB", b.Requested.MemoryGB, h.Requested.MemoryGB),
+			Source:      SourceInferred,
+		})
+	}
+
+	if b.Machine != nil && h.Machine != nil &&
+		b.Machine.RecommendedType != b.Machine.InstanceType &&
+		h.Machine.InstanceType == b.Machine.RecommendedType {
+		out = append(out, types.AppliedFix{
+			Resource:    b.Resource,
+			Intent:      "rightsize_machine_type",
+			Description: fmt.Sprintf("%s → %s", b.Machine.InstanceType, h.Machine.InstanceType),
+			Source:      SourceInferred,
+		})
+	}
+
+	return out
+}
+
+// Markdown renders d for PR comments and reports. Unchanged resources are
+// left out.
+func Markdown(d types.ScanDiff) string {
+	var b strings.Builder
+
+	s := d.Summary
+	title := "## CostGuard scan diff"
+	if d.BaseID != "" || d.HeadID != "" {
+		title += fmt.Sprintf(" (%s → %s)", orDash(d.BaseID), orDash(d.HeadID))
+	}
+	b.WriteString(title + "\n\n")
+
+	b.WriteString("| | Base | Head | Delta |\n|---|---:|---:|---:|\n")
+	b.WriteString(fmt.Sprintf("| Monthly cost | $%.2f | $%.2f | %s |\n", s.BaseCostUSD, s.HeadCostUSD, signedUSD(s.CostDeltaUSD)))
+	b.WriteString(fmt.Sprintf("| Potential savings | $%.2f | $%.2f | %s |\n\n", s.BaseSavingsUSD, s.HeadSavingsUSD, signedUSD(s.SavingsDeltaUSD)))
+	b.WriteString(fmt.Sprintf("%d added, %d removed, %d changed.\n\n", s.Added, s.Removed, s.Changed))
+
+	rows := 0
+	for _, c := range d.Resources {
+		if c.Status == types.ChangeUnchanged {
+			continue
+		}
+		if rows == 0 {
+			b.WriteString("### Resources\n\n")
+			b.WriteString("| Resource | Status | Cost | Δ Cost | Waste | Requested | Usage P95 |\n")
+			b.WriteString("|---|---|---:|---:|---:|---|---|\n")
+		}
+		rows++
+		b.WriteString(fmt.Sprintf("| `%s` (%s) | %s | $%.2f → $%.2f | %s | %.0f%% → %.0f%% | %s | %s |\n",
+			c.Resource, c.Provider, c.Status,
+			c.BaseCostUSD, c.HeadCostUSD, signedUSD(c.CostDeltaUSD),
+			c.BaseWastePct, c.HeadWastePct,
+			RequestsChange(c.BaseRequested, c.HeadRequested, c.BaseInstanceType, c.HeadInstanceType),
+			RequestsChange(c.BaseUsageP95, c.HeadUsageP95, "", ""),
+		))
+	}
+	if rows > 0 {
+		b.WriteString("\n")
+	}
+
+	if len(d.FixesApplied) > 0 {
+		b.WriteString("### Fixes applied\n\n")
+		for _, f := range d.FixesApplied {
+			b.WriteString(fmt.Sprintf("- **%s** (%s, %s): %s\n", f.Resource, f.Intent, f.Source, f.Description))
+		}
+	}
+
+	return b.String()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func signedUSD(v float64) string {
+	if v < 0 {
+		return fmt.Sprintf("-$%.2f", -v)
+	}
+	return fmt.Sprintf("+$%.2f", v)
+}
+
+// RequestsChange summarises a CPU/memory (and machine type) change as
+// "500m/1.00GB → 200m/0.50GB", or a single value when nothing changed.
+func RequestsChange(before, after types.Requests, beforeType, afterType string) string {
+	format := func(r types.Requests, t string) string {
+		if t != "" {
+			return t
+		}
+		if r.CpuMilli == 0 && r.MemoryGB == 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%.0fm/%.2fGB", r.CpuMilli, r.MemoryGB)
+	}
+
+	from, to := format(before, beforeType), format(after, afterType)
+	if from == to {
+		return from
+	}
+	return from + " → " + to
+}
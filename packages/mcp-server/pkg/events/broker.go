@@ -0,0 +1,50 @@
+package events
+
+import "sync"
+
+// SubscriberBuffer is how many events a subscriber may fall behind before
+// it is dropped.
+const SubscriberBuffer = 256
+
+// Broker fans events out to live subscribers. Publishing never blocks: a
+// subscriber whose buffer is full is dropped and its channel closed, and it
+// is expected to reconnect and catch up from the log.
+type Broker struct {
+	mu   sync.Mutex
+	subs map[chan Event]struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{subs: map[chan Event]struct{}{}}
+}
+
+func (b *Broker) Subscribe() chan Event {
+	ch := make(chan Event, SubscriberBuffer)
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+	return ch
+}
+
+// Unsubscribe removes ch. It is safe to call after ch was dropped.
+func (b *Broker) Unsubscribe(ch chan Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subs[ch]; ok {
+		delete(b.subs, ch)
+		close(ch)
+	}
+}
+
+func (b *Broker) Publish(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs {
+		select {
+		case ch <- e:
+		default:
+			delete(b.subs, ch)
+			close(ch)
+		}
+	}
+}
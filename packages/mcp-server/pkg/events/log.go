@@ -0,0 +1,205 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const DefaultPath = ".costguard/events.jsonl"
+
+const (
+	tailChunk   = 64 * 1024
+	maxLineSize = 16 * 1024 * 1024
+)
+
+type Type string
+
+const (
+	ScanExecuted     Type = "SCAN_EXECUTED"
+	FixPlanGenerated Type = "FIX_PLAN_GENERATED"
+	DecisionMade     Type = "DECISION_MADE"
+	FixApplied       Type = "FIX_APPLIED"
+	PRCreated        Type = "PR_CREATED"
+	AnomalyDetected  Type = "ANOMALY_DETECTED"
+	Error            Type = "ERROR"
+)
+
+// Event is one line of the event log. IDs increase by one per event.
+type Event struct {
+	ID        string    `json:"id"`
+	Type      Type      `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	Source    string    `json:"source"`
+	Resource  string    `json:"resource,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	Payload   any       `json:"payload,omitempty"`
+}
+
+// Log appends events to a JSONL file and publishes them to its Broker. The
+// file and its directory are created on the first append. Several processes
+// may append to the same file: appends hold an exclusive file lock and take
+// the next ID from the last line written. Readers take no lock.
+type Log struct {
+	path string
+	mu   sync.Mutex
+
+	// Broker receives every event this Log writes, in ID order.
+	Broker *Broker
+}
+
+// Default receives events from Emit. A nil Default discards them.
+var Default *Log
+
+func Open(path string) (*Log, error) {
+	f, err := os.Open(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if f != nil {
+		f.Close()
+	}
+	return &Log{path: path, Broker: NewBroker()}, nil
+}
+
+// each calls fn for every event in the file from byte offset from until fn
+// returns false. Lines that don't parse, such as a torn or in-flight write,
+// are skipped.
+func (l *Log) each(from int64, fn func(Event) bool) error {
+	f, err := os.Open(l.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(from, io.SeekStart); err != nil {
+		return err
+	}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		var e Event
+		if json.Unmarshal(scanner.Bytes(), &e) != nil {
+			continue
+		}
+		if !fn(e) {
+			break
+		}
+	}
+	return scanner.Err()
+}
+
+// Append assigns e the next ID, stamps it if needed and writes it.
+func (l *Log) Append(e Event) (Event, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return e, err
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return e, err
+	}
+	defer f.Close()
+
+	if err := lockFile(f); err != nil {
+		return e, err
+	}
+	defer unlockFile(f)
+
+	seq, torn, err := lastID(f)
+	if err != nil {
+		return e, err
+	}
+
+	e.ID = strconv.FormatUint(seq+1, 10)
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now().UTC()
+	}
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return e, err
+	}
+	if torn {
+		// Finish the torn line so it doesn't swallow this one.
+		line = append([]byte{'\n'}, line...)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return e, err
+	}
+	l.Broker.Publish(e)
+	return e, nil
+}
+
+// lastID reads the file backwards to the last complete line that parses.
+// torn reports whether the file ends in a partial line.
+func lastID(f *os.File) (id uint64, torn bool, err error) {
+	info, err := f.Stat()
+	if err != nil {
+		return 0, false, err
+	}
+
+	var rest []byte
+	for pos := info.Size(); pos > 0; {
+		n := min(pos, tailChunk)
+		pos -= n
+		chunk := make([]byte, n, int(n)+len(rest))
+		if _, err := f.ReadAt(chunk, pos); err != nil {
+			return 0, false, err
+		}
+		if pos+n == info.Size() {
+			torn = chunk[n-1] != '\n'
+		}
+
+		// Every line but the first is complete; the first may continue in
+		// the previous chunk unless this is the start of the file.
+		lines := bytes.Split(append(chunk, rest...), []byte{'\n'})
+		first := 1
+		if pos == 0 {
+			first = 0
+		}
+		for i := len(lines) - 1; i >= first; i-- {
+			if id, ok := lineID(lines[i]); ok {
+				return id, torn, nil
+			}
+		}
+		rest = lines[0]
+	}
+	return 0, torn, nil
+}
+
+func lineID(line []byte) (uint64, bool) {
+	var e struct {
+		ID string `json:"id"`
+	}
+	if json.Unmarshal(line, &e) != nil {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(e.ID, 10, 64)
+	return id, err == nil
+}
+
+// Emit appends e to Default. Event logging is best effort: a log that
+// cannot be written never fails the operation being logged.
+func Emit(e Event) {
+	if Default == nil {
+		return
+	}
+	Default.Append(e)
+}
+
+// EmitError logs err as an ERROR event from source.
+func EmitError(source, resource string, err error) {
+	Emit(Event{Type: Error, Source: source, Resource: resource, Message: err.Error()})
+}
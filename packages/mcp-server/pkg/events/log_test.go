@@ -0,0 +1,205 @@
+package events
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func openTestLog(t *testing.T) *Log {
+	t.Helper()
+	l, err := Open(filepath.Join(t.TempDir(), "events.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func appendN(t *testing.T, l *Log, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		typ := ScanExecuted
+		if i%2 == 1 {
+			typ = FixPlanGenerated
+		}
+		if _, err := l.Append(Event{Type: typ, Source: "test", Resource: fmt.Sprintf("r%d", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAppendIDs(t *testing.T) {
+	l := openTestLog(t)
+	appendN(t, l, 3)
+
+	// A second writer on the same file continues from the last line.
+	other, err := Open(l.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := other.Append(Event{Type: Error, Source: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.ID != "4" {
+		t.Errorf("ID = %s, want 4", e.ID)
+	}
+	if e.Timestamp.IsZero() {
+		t.Error("Timestamp not set")
+	}
+}
+
+func TestAppendAfterTornLine(t *testing.T) {
+	l := openTestLog(t)
+	appendN(t, l, 2)
+
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"id":"3","type":"SCAN_EX`)
+	f.Close()
+
+	e, err := l.Append(Event{Type: Error, Source: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.ID != "3" {
+		t.Errorf("ID = %s, want 3 after a torn write", e.ID)
+	}
+
+	page, err := l.List(Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Events) != 3 || page.Events[2].Type != Error {
+		t.Errorf("List = %+v, want the torn line skipped and the new event last", page.Events)
+	}
+}
+
+func TestConcurrentAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l, err := Open(path)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			for i := 0; i < 25; i++ {
+				if _, err := l.Append(Event{Type: ScanExecuted, Source: "test"}); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	l, _ := Open(path)
+	page, err := l.List(Query{Limit: MaxLimit})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Events) != 100 {
+		t.Fatalf("got %d events, want 100", len(page.Events))
+	}
+	for i, e := range page.Events {
+		if e.ID != strconv.Itoa(i+1) {
+			t.Fatalf("event %d has ID %s, want %d", i, e.ID, i+1)
+		}
+	}
+}
+
+func TestOffsetAfter(t *testing.T) {
+	l := openTestLog(t)
+	appendN(t, l, 5)
+
+	// Lines that don't parse sit between events and must not confuse the
+	// search.
+	f, _ := os.OpenFile(l.path, os.O_APPEND|os.O_WRONLY, 0o644)
+	f.WriteString("not json\n\n" + `{"type":"missing id"}` + "\n")
+	f.Close()
+	appendN(t, l, 5)
+
+	raw, err := os.ReadFile(l.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// want[id] is the offset of the line holding event id.
+	want := map[uint64]int64{}
+	offset := int64(0)
+	for _, line := range bytes.SplitAfter(raw, []byte{'\n'}) {
+		if id, ok := lineID(line); ok {
+			want[id] = offset
+		}
+		offset += int64(len(line))
+	}
+
+	for after := uint64(0); after <= 11; after++ {
+		got, err := l.offsetAfter(after)
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp, ok := want[after+1]
+		if !ok {
+			exp = int64(len(raw))
+		}
+		if got != exp {
+			t.Errorf("offsetAfter(%d) = %d, want %d", after, got, exp)
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	l := openTestLog(t)
+
+	page, err := l.List(Query{})
+	if err != nil || len(page.Events) != 0 {
+		t.Fatalf("List on a missing file = %+v, %v, want empty", page, err)
+	}
+
+	appendN(t, l, 10)
+
+	tests := []struct {
+		name string
+		q    Query
+		ids  []string
+		next string
+	}{
+		{"all", Query{}, []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10"}, ""},
+		{"first page", Query{Limit: 3}, []string{"1", "2", "3"}, "3"},
+		{"second page", Query{Limit: 3, After: "3"}, []string{"4", "5", "6"}, "6"},
+		{"last page", Query{Limit: 4, After: "6"}, []string{"7", "8", "9", "10"}, ""},
+		{"past the end", Query{After: "10"}, []string{}, ""},
+		{"by type", Query{Types: []Type{FixPlanGenerated}, Limit: 2}, []string{"2", "4"}, "4"},
+		{"by type after cursor", Query{Types: []Type{FixPlanGenerated}, After: "4"}, []string{"6", "8", "10"}, ""},
+		{"by resource", Query{Resource: "r7"}, []string{"8"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := l.List(tt.q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids := []string{}
+			for _, e := range page.Events {
+				ids = append(ids, e.ID)
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(tt.ids) {
+				t.Errorf("IDs = %v, want %v", ids, tt.ids)
+			}
+			if page.Next != tt.next {
+				t.Errorf("Next = %q, want %q", page.Next, tt.next)
+			}
+		})
+	}
+}
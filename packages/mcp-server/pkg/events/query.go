@@ -0,0 +1,156 @@
+package events
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+// Query filters the log. Zero values match everything. Events come oldest
+// first, starting after the event with ID After.
+type Query struct {
+	Types    []Type
+	Source   string
+	Resource string
+	Since    time.Time
+	Until    time.Time
+	After    string
+	Limit    int
+}
+
+// Page is one page of matching events. Next is the After cursor for the
+// following page and is empty on the last page.
+type Page struct {
+	Events []Event `json:"events"`
+	Next   string  `json:"next,omitempty"`
+}
+
+// Matches reports whether e passes the filters. After and Limit are not
+// filters and are ignored.
+func (q Query) Matches(e Event) bool {
+	if len(q.Types) > 0 {
+		found := false
+		for _, t := range q.Types {
+			found = found || e.Type == t
+		}
+		if !found {
+			return false
+		}
+	}
+	return (q.Source == "" || e.Source == q.Source) &&
+		(q.Resource == "" || e.Resource == q.Resource) &&
+		(q.Since.IsZero() || !e.Timestamp.Before(q.Since)) &&
+		(q.Until.IsZero() || e.Timestamp.Before(q.Until))
+}
+
+// List returns the page of matching events after q.After. IDs only grow
+// through the file, so the cursor is found by binary search instead of a
+// scan from the start. List takes no lock and may run alongside appends.
+func (l *Log) List(q Query) (Page, error) {
+	page := Page{Events: []Event{}}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	limit = min(limit, MaxLimit)
+
+	after, _ := strconv.ParseUint(q.After, 10, 64)
+	from, err := l.offsetAfter(after)
+	if err != nil {
+		return page, err
+	}
+
+	err = l.each(from, func(e Event) bool {
+		if id, err := strconv.ParseUint(e.ID, 10, 64); err != nil || id <= after {
+			return true
+		}
+		if !q.Matches(e) {
+			return true
+		}
+		if len(page.Events) == limit {
+			page.Next = page.Events[limit-1].ID
+			return false
+		}
+		page.Events = append(page.Events, e)
+		return true
+	})
+	return page, err
+}
+
+// offsetAfter returns the offset of the first line with an ID above after,
+// or the end of the file.
+func (l *Log) offsetAfter(after uint64) (int64, error) {
+	if after == 0 {
+		return 0, nil
+	}
+	f, err := os.Open(l.path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	lo, hi := int64(0), info.Size()
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		start, id, ok, err := lineFrom(f, mid, info.Size())
+		if err != nil {
+			return 0, err
+		}
+		if ok && id <= after {
+			lo = start + 1
+		} else {
+			hi = mid
+		}
+	}
+	start, _, _, err := lineFrom(f, lo, info.Size())
+	return start, err
+}
+
+// lineFrom finds the first parseable line starting at or after pos and
+// returns its offset and ID. ok is false when there is none before size.
+func lineFrom(f *os.File, pos, size int64) (start int64, id uint64, ok bool, err error) {
+	start = pos
+	if pos > 0 {
+		// pos starts a line only if the byte before it ends one.
+		start = pos - 1
+	}
+	r := bufio.NewReader(io.NewSectionReader(f, start, size-start))
+	for pos > 0 {
+		skipped, err := r.ReadSlice('\n')
+		start += int64(len(skipped))
+		if err == nil {
+			break
+		}
+		if err != bufio.ErrBufferFull {
+			return size, 0, false, nil
+		}
+	}
+
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			// A final line without a newline is still being written.
+			return size, 0, false, nil
+		}
+		if id, ok := lineID(line); ok {
+			return start, id, true, nil
+		}
+		start += int64(len(line))
+	}
+}
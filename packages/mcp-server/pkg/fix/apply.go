@@ -7,13 +7,41 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/events"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/pricing"
 	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
 )
 
 func ApplyFix(action types.FixAction) error {
+	if err := applyFix(action); err != nil {
+		events.EmitError("fix", action.Resource, err)
+		return err
+	}
+
+	events.Emit(events.Event{
+		Type:     events.FixApplied,
+		Source:   "fix",
+		Resource: action.Resource,
+		Message:  action.Description,
+		Payload:  action,
+	})
+	return nil
+}
+
+func applyFix(action types.FixAction) error {
 	switch action.Provider {
-	case types.ProviderKubernetes:
+	case types.ProviderKubernetes, types.ProviderKubernetesStorage, types.ProviderGCPCloudRun:
 		return applyK8sFix(action)
+	case types.ProviderGCPGCE:
+		return applyTerraformFix(action, []string{"google_compute_instance", "google_compute_instance_template"})
+	case types.ProviderAzureContainerApps, types.ProviderAzureVM:
+		return applyAzureFix(action)
+	case types.ProviderAWSECSFargate:
+		return applyECSFix(action)
+	case types.ProviderAWSRDS:
+		return applyRDSFix(action)
+	case types.ProviderAWSS3, types.ProviderObjectStorage:
+		return applyLifecycleFix(action)
 	default:
 		return fmt.Errorf("unsupported provider: %s", action.Provider)
 	}
@@ -85,7 +113,11 @@ func findK8sManifests() ([]string, error) {
 				if strings.Contains(string(content), "apiVersion:") &&
 					(strings.Contains(string(content), "kind: Deployment") ||
 						strings.Contains(string(content), "kind: StatefulSet") ||
-						strings.Contains(string(content), "kind: Pod")) {
+						strings.Contains(string(content), "kind: Pod") ||
+						strings.Contains(string(content), "kind: HorizontalPodAutoscaler") ||
+						strings.Contains(string(content), "kind: PersistentVolumeClaim") ||
+						strings.Contains(string(content), "kind: Service") ||
+						strings.Contains(string(content), "serving.knative.dev")) {
 					files = append(files, p)
 				}
 			}
@@ -123,6 +155,22 @@ func updateK8sManifest(filePath string, action types.FixAction) error {
 func updateK8sManifestRegex(filePath string, content []byte, action types.FixAction) error {
 	contentStr := string(content)
 
+	if action.Action.Operation == "delete" {
+		return removeK8sDocuments(filePath, contentStr, action)
+	}
+
+	if action.Action.Operation == "add_placement" {
+		return addK8sPlacement(filePath, contentStr, action)
+	}
+
+	if action.Action.Operation == "add_annotation" {
+		return addK8sServiceAnnotation(filePath, contentStr, action)
+	}
+
+	if kinds, ok := documentFieldKinds[action.Action.Field]; ok {
+		return updateK8sDocumentField(filePath, contentStr, kinds, action)
+	}
+
 	var pattern *regexp.Regexp
 	var replacement string
 
@@ -177,6 +225,296 @@ func updateK8sManifestRegex(filePath string, content []byte, action types.FixAct
 	return os.WriteFile(filePath, []byte(strings.Join(lines, "\n")), 0644)
 }
 
+// documentFieldKinds lists fields that live at a fixed place in a single
+// document rather than per container, and the kinds that may hold them.
+var documentFieldKinds = map[string][]string{
+	"spec.replicas":    {"Deployment", "StatefulSet"},
+	"spec.minReplicas": {"HorizontalPodAutoscaler"},
+	"spec.maxReplicas": {"HorizontalPodAutoscaler"},
+	"spec.metrics.resource.target.averageUtilization":           {"HorizontalPodAutoscaler"},
+	"spec.resources.requests.storage":                           {"PersistentVolumeClaim"},
+	"spec.storageClassName":                                     {"PersistentVolumeClaim"},
+	"spec.volumeClaimTemplates.spec.resources.requests.storage": {"StatefulSet"},
+	"spec.volumeClaimTemplates.spec.storageClassName":           {"StatefulSet"},
+	"spec.template.spec.containers.resources.limits.cpu":        {"Service"},
+	"spec.template.spec.containers.resources.limits.memory":     {"Service"},
+	"spec.template.spec.containerConcurrency":                   {"Service"},
+	"spec.template.metadata.annotations.minScale":               {"Service"},
+}
+
+func documentFieldPattern(action types.FixAction) (*regexp.Regexp, string) {
+	value := action.Action.Value
+
+	switch action.Action.Field {
+	case "spec.replicas":
+		return regexp.MustCompile(`(?m)^(\s*)replicas:\s*\d+`), fmt.Sprintf("${1}replicas: %.0f", value)
+	case "spec.minReplicas":
+		return regexp.MustCompile(`(?m)^(\s*)minReplicas:\s*\d+`), fmt.Sprintf("${1}minReplicas: %.0f", value)
+	case "spec.maxReplicas":
+		return regexp.MustCompile(`(?m)^(\s*)maxReplicas:\s*\d+`), fmt.Sprintf("${1}maxReplicas: %.0f", value)
+	case "spec.resources.requests.storage", "spec.volumeClaimTemplates.spec.resources.requests.storage":
+		return regexp.MustCompile(`(?m)^(\s*)storage:\s*["']?\d+(?:\.\d+)?(?:Ki|Mi|Gi|Ti|K|M|G|T)?["']?`),
+			fmt.Sprintf("${1}storage: \"%.0fGi\"", value)
+	case "spec.template.spec.containers.resources.limits.cpu":
+		return regexp.MustCompile(`(?m)^(\s*)cpu:\s*["']?\d+(?:\.\d+)?m?["']?`),
+			fmt.Sprintf("${1}cpu: \"%g\"", value)
+	case "spec.template.spec.containers.resources.limits.memory":
+		return regexp.MustCompile(`(?m)^(\s*)memory:\s*["']?\d+(?:\.\d+)?(?:Mi|Gi|M|G)?["']?`),
+			fmt.Sprintf("${1}memory: \"%.0fMi\"", value*1024)
+	case "spec.template.spec.containerConcurrency":
+		return regexp.MustCompile(`(?m)^(\s*)containerConcurrency:\s*\d+`),
+			fmt.Sprintf("${1}containerConcurrency: %.0f", value)
+	case "spec.template.metadata.annotations.minScale":
+		return regexp.MustCompile(`(?m)^(\s*)(autoscaling\.knative\.dev/minScale|run\.googleapis\.com/minScale):\s*["']?\d+["']?`),
+			fmt.Sprintf("${1}${2}: \"%.0f\"", value)
+	case "spec.storageClassName", "spec.volumeClaimTemplates.spec.storageClassName":
+		return regexp.MustCompile(`(?m)^(\s*)storageClassName:\s*\S+`),
+			fmt.Sprintf("${1}storageClassName: \"%s\"", action.Action.Target)
+	default:
+		return regexp.MustCompile(`(?m)^(\s*)(averageUtilization|targetCPUUtilizationPercentage):\s*\d+`),
+			fmt.Sprintf("${1}${2}: %.0f", value)
+	}
+}
+
+// updateK8sDocumentField edits the first "---"-separated document of one of
+// the given kinds that mentions the resource.
+func updateK8sDocumentField(filePath, content string, kinds []string, action types.FixAction) error {
+	pattern, replacement := documentFieldPattern(action)
+
+	docs := strings.Split(content, "\n---")
+	for i, doc := range docs {
+		if !strings.Contains(doc, action.Resource) || !pattern.MatchString(doc) {
+			continue
+		}
+
+		matchesKind := false
+		for _, kind := range kinds {
+			if strings.Contains(doc, "kind: "+kind) {
+				matchesKind = true
+				break
+			}
+		}
+		if !matchesKind {
+			continue
+		}
+
+		loc := pattern.FindStringIndex(doc)
+		docs[i] = doc[:loc[0]] + pattern.ReplaceAllString(doc[loc[0]:loc[1]], replacement) + doc[loc[1]:]
+
+		return os.WriteFile(filePath, []byte(strings.Join(docs, "\n---")), 0644)
+	}
+
+	return fmt.Errorf("no %s for %s declares %s", strings.Join(kinds, "/"), action.Resource, action.Action.Field)
+}
+
+// deleteKinds lists the kinds whose documents a delete action removes, by
+// the provider of the resource being deleted.
+var deleteKinds = map[types.Provider][]string{
+	types.ProviderKubernetes:        {"Deployment", "StatefulSet", "DaemonSet", "ReplicaSet"},
+	types.ProviderKubernetesStorage: {"PersistentVolumeClaim"},
+}
+
+var (
+	topLevelKind     = regexp.MustCompile(`(?m)^kind:\s*["']?([A-Za-z]+)["']?\s*$`)
+	topLevelMetadata = regexp.MustCompile(`(?m)^metadata:\s*$`)
+	metadataName     = regexp.MustCompile(`^(\s+)name:\s*["']?([^"'\s#]+)["']?\s*(#.*)?$`)
+)
+
+// documentKindAndName returns a document's top-level kind and metadata.name,
+// ignoring names nested deeper such as containers, ports or volumes.
+func documentKindAndName(doc string) (string, string) {
+	kind := ""
+	if m := topLevelKind.FindStringSubmatch(doc); m != nil {
+		kind = m[1]
+	}
+
+	loc := topLevelMetadata.FindStringIndex(doc)
+	if loc == nil {
+		return kind, ""
+	}
+
+	indent := -1
+	for _, line := range strings.Split(doc[loc[1]:], "\n")[1:] {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lineIndent := len(line) - len(strings.TrimLeft(line, " "))
+		if lineIndent == 0 {
+			break
+		}
+		if indent < 0 {
+			indent = lineIndent
+		}
+		if lineIndent != indent {
+			continue
+		}
+		if m := metadataName.FindStringSubmatch(line); m != nil {
+			return kind, m[2]
+		}
+	}
+	return kind, ""
+}
+
+// removeK8sDocuments drops the documents of the resource's kind whose
+// metadata.name is the resource, and the file itself once nothing else is
+// left in it.
+func removeK8sDocuments(filePath, content string, action types.FixAction) error {
+	kinds := deleteKinds[action.Provider]
+
+	kept := []string{}
+	removed := 0
+	for _, doc := range strings.Split(content, "\n---") {
+		kind, name := documentKindAndName(doc)
+		if name == action.Resource && containsString(kinds, kind) {
+			removed++
+			continue
+		}
+		kept = append(kept, doc)
+	}
+
+	if removed == 0 {
+		return fmt.Errorf("no manifest in %s names %s", filePath, action.Resource)
+	}
+
+	if strings.TrimSpace(strings.Join(kept, "")) == "" {
+		return os.Remove(filePath)
+	}
+
+	return os.WriteFile(filePath, []byte(strings.TrimPrefix(strings.Join(kept, "\n---"), "\n")), 0644)
+}
+
+// addK8sPlacement pins the pod template of the resource's workload to a node
+// pool, or to spot capacity along with the toleration spot nodes are tainted
+// with. Entries are added next to the pod's containers: key.
+func addK8sPlacement(filePath, content string, action types.FixAction) error {
+	k8s := pricing.Default.Kubernetes
+
+	selector := pricing.Label{Key: k8s.NodePoolLabel, Value: action.Action.Target}
+	var toleration *pricing.Label
+	if action.Action.Target == "spot" {
+		selector = k8s.SpotNodeSelector
+		toleration = &k8s.SpotToleration
+	}
+
+	docs := strings.Split(content, "\n---")
+	for i, doc := range docs {
+		if !strings.Contains(doc, action.Resource) ||
+			!(strings.Contains(doc, "kind: Deployment") || strings.Contains(doc, "kind: StatefulSet")) {
+			continue
+		}
+
+		lines := strings.Split(doc, "\n")
+		containersAt := -1
+		for j, line := range lines {
+			if strings.TrimSpace(line) == "containers:" {
+				containersAt = j
+				break
+			}
+		}
+		if containersAt < 0 {
+			continue
+		}
+
+		indent := lines[containersAt][:len(lines[containersAt])-len(strings.TrimLeft(lines[containersAt], " "))]
+		selectorLine := fmt.Sprintf("%s  %s: \"%s\"", indent, selector.Key, selector.Value)
+
+		lines = upsertBlockEntry(lines, indent+"nodeSelector:", selectorLine, indent+"  "+selector.Key+":", containersAt)
+
+		if toleration != nil {
+			tolerationLines := []string{
+				fmt.Sprintf("%s  - key: \"%s\"", indent, toleration.Key),
+				indent + "    operator: \"Equal\"",
+				fmt.Sprintf("%s    value: \"%s\"", indent, toleration.Value),
+				indent + "    effect: \"NoSchedule\"",
+			}
+			if !strings.Contains(doc, fmt.Sprintf("key: \"%s\"", toleration.Key)) {
+				lines = insertBlock(lines, indent+"tolerations:", tolerationLines)
+			}
+		}
+
+		docs[i] = strings.Join(lines, "\n")
+		return os.WriteFile(filePath, []byte(strings.Join(docs, "\n---")), 0644)
+	}
+
+	return fmt.Errorf("no Deployment or StatefulSet pod template for %s", action.Resource)
+}
+
+// addK8sServiceAnnotation sets the "key=value" target as an annotation on the
+// Service named after the action's resource.
+func addK8sServiceAnnotation(filePath, content string, action types.FixAction) error {
+	key, value, _ := strings.Cut(action.Action.Target, "=")
+	namePattern := regexp.MustCompile(`(?m)^  name:\s*["']?` + regexp.QuoteMeta(action.Resource) + `["']?\s*$`)
+
+	docs := strings.Split(content, "\n---")
+	for i, doc := range docs {
+		if !strings.Contains(doc, "kind: Service") || strings.Contains(doc, "serving.knative.dev") || !namePattern.MatchString(doc) {
+			continue
+		}
+
+		lines := strings.Split(doc, "\n")
+		metadataAt := -1
+		for j, line := range lines {
+			if line == "metadata:" {
+				metadataAt = j
+				break
+			}
+		}
+		if metadataAt < 0 {
+			continue
+		}
+
+		entry := fmt.Sprintf("    %s: %q", key, value)
+		lines = upsertBlockEntry(lines, "  annotations:", entry, "    "+key+":", metadataAt+1)
+
+		docs[i] = strings.Join(lines, "\n")
+		return os.WriteFile(filePath, []byte(strings.Join(docs, "\n---")), 0644)
+	}
+
+	return fmt.Errorf("no Service named %s", action.Resource)
+}
+
+// upsertBlockEntry sets entry under header, replacing any line that starts
+// with keyPrefix, and creates the header just above the line at before when
+// it does not exist yet.
+func upsertBlockEntry(lines []string, header, entry, keyPrefix string, before int) []string {
+	childIndent := strings.Repeat(" ", len(header)-len(strings.TrimLeft(header, " "))+2)
+
+	for i, line := range lines {
+		if line != header {
+			continue
+		}
+		for j := i + 1; j < len(lines) && strings.HasPrefix(lines[j], childIndent); j++ {
+			if strings.HasPrefix(lines[j], keyPrefix) {
+				lines[j] = entry
+				return lines
+			}
+		}
+		return append(lines[:i+1], append([]string{entry}, lines[i+1:]...)...)
+	}
+
+	block := []string{header, entry}
+	return append(lines[:before], append(block, lines[before:]...)...)
+}
+
+// insertBlock appends entries to an existing header, or adds the header with
+// the entries just above the containers: key that shares its indentation.
+func insertBlock(lines []string, header string, entries []string) []string {
+	for i, line := range lines {
+		if line == header {
+			return append(lines[:i+1], append(entries, lines[i+1:]...)...)
+		}
+	}
+
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "containers:" {
+			block := append([]string{header}, entries...)
+			return append(lines[:i], append(block, lines[i:]...)...)
+		}
+	}
+
+	return lines
+}
+
 func addResourcesSection(filePath, content string, action types.FixAction) error {
 
 	lines := strings.Split(content, "\n")
@@ -0,0 +1,141 @@
+package fix
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+// azureTemplateKeys maps action fields to the property name used by Bicep,
+// ARM JSON and Container Apps YAML alike.
+var azureTemplateKeys = map[string]string{
+	"properties.template.containers.resources.cpu":    "cpu",
+	"properties.template.containers.resources.memory": "memory",
+	"properties.template.scale.minReplicas":           "minReplicas",
+	"vmSize":                                          "vmSize",
+}
+
+var azureResourceTypes = map[types.Provider]string{
+	types.ProviderAzureContainerApps: "Microsoft.App/containerApps",
+	types.ProviderAzureVM:            "Microsoft.Compute/virtualMachines",
+}
+
+// applyAzureFix edits the Bicep, ARM or Container Apps YAML definition of the
+// action's resource. VM sizes fall back to Terraform when no template
+// declares the machine.
+func applyAzureFix(action types.FixAction) error {
+	key, ok := azureTemplateKeys[action.Action.Field]
+	if !ok {
+		return fmt.Errorf("unsupported field for %s: %s", action.Provider, action.Action.Field)
+	}
+
+	files, err := findAzureTemplates(azureResourceTypes[action.Provider])
+	if err != nil {
+		return fmt.Errorf("failed to find Azure templates: %w", err)
+	}
+
+	value := azureTemplateValue(action)
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+
+		updated, ok := setAzureProperty(string(content), action.Resource, key, value)
+		if ok {
+			return os.WriteFile(file, []byte(updated), 0644)
+		}
+	}
+
+	if action.Provider == types.ProviderAzureVM {
+		tf := action
+		tf.Action.Field = "size"
+		return applyTerraformFix(tf, []string{"azurerm_linux_virtual_machine", "azurerm_windows_virtual_machine", "azurerm_virtual_machine"})
+	}
+
+	return fmt.Errorf("no Azure template for %s sets %s", action.Resource, key)
+}
+
+func azureTemplateValue(action types.FixAction) string {
+	switch {
+	case action.Action.Target != "":
+		return action.Action.Target
+	case action.Action.Unit == "Gi":
+		return fmt.Sprintf("%gGi", action.Action.Value)
+	default:
+		return fmt.Sprintf("%g", action.Action.Value)
+	}
+}
+
+// findAzureTemplates returns Bicep, JSON and YAML files that declare
+// resourceType.
+func findAzureTemplates(resourceType string) ([]string, error) {
+	var files []string
+
+	err := filepath.Walk(".", func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if info.IsDir() {
+			if name := info.Name(); name == ".git" || name == "node_modules" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		switch filepath.Ext(p) {
+		case ".bicep", ".json", ".yaml", ".yml":
+		default:
+			return nil
+		}
+
+		content, err := os.ReadFile(p)
+		if err == nil && strings.Contains(strings.ToLower(string(content)), strings.ToLower(resourceType)) {
+			files = append(files, p)
+		}
+		return nil
+	})
+
+	return files, err
+}
+
+// setAzureProperty rewrites the first key property following the resource's
+// name declaration, keeping the value's existing quoting: json('0.5') in
+// Bicep, "[json('0.5')]" or a bare number in ARM, plain scalars in YAML.
+func setAzureProperty(content, name, key, value string) (string, bool) {
+	namePattern := regexp.MustCompile(`["']?name["']?\s*:\s*["']?` + regexp.QuoteMeta(name) + `["']?\s*(,|$|\n|\r)`)
+	keyPattern := regexp.MustCompile(`(?:^|[^\w])(["']?` + regexp.QuoteMeta(key) + `["']?\s*:\s*)("\[json\('[^']*'\)\]"|json\('[^']*'\)|"[^"]*"|'[^']*'|[^\s,}]+)`)
+
+	nameLoc := namePattern.FindStringIndex(content)
+	if nameLoc == nil {
+		return content, false
+	}
+
+	rest := content[nameLoc[1]:]
+	loc := keyPattern.FindStringSubmatchIndex(rest)
+	if loc == nil {
+		return content, false
+	}
+
+	old := rest[loc[4]:loc[5]]
+	var replacement string
+	switch {
+	case strings.HasPrefix(old, `"[json(`):
+		replacement = fmt.Sprintf(`"[json('%s')]"`, value)
+	case strings.HasPrefix(old, "json("):
+		replacement = fmt.Sprintf("json('%s')", value)
+	case strings.HasPrefix(old, `"`):
+		replacement = fmt.Sprintf("%q", value)
+	case strings.HasPrefix(old, "'"):
+		replacement = "'" + value + "'"
+	default:
+		replacement = value
+	}
+
+	return content[:nameLoc[1]] + rest[:loc[4]] + replacement + rest[loc[5]:], true
+}
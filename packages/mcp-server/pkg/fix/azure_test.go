@@ -0,0 +1,170 @@
+package fix
+
+import (
+	"os"
+	"testing"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+func TestSetAzureProperty(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		resource string
+		key      string
+		value    string
+		want     string
+		changed  bool
+	}{
+		{
+			name: "bicep json()",
+			content: `resource app 'Microsoft.App/containerApps@2023-05-01' = {
+  name: 'checkout'
+  properties: {
+    template: {
+      containers: [
+        {
+          name: 'app'
+          resources: {
+            cpu: json('1.0')
+            memory: '2Gi'
+          }
+        }
+      ]
+    }
+  }
+}`,
+			key:   "cpu",
+			value: "0.5",
+			want: `resource app 'Microsoft.App/containerApps@2023-05-01' = {
+  name: 'checkout'
+  properties: {
+    template: {
+      containers: [
+        {
+          name: 'app'
+          resources: {
+            cpu: json('0.5')
+            memory: '2Gi'
+          }
+        }
+      ]
+    }
+  }
+}`,
+			changed: true,
+		},
+		{
+			name:    "bicep quoted string",
+			content: "name: 'checkout'\nmemory: '2Gi'\n",
+			key:     "memory",
+			value:   "1Gi",
+			want:    "name: 'checkout'\nmemory: '1Gi'\n",
+			changed: true,
+		},
+		{
+			name:    "arm expression",
+			content: `{"type": "Microsoft.App/containerApps", "name": "checkout", "properties": {"cpu": "[json('1.0')]"}}`,
+			key:     "cpu",
+			value:   "0.25",
+			want:    `{"type": "Microsoft.App/containerApps", "name": "checkout", "properties": {"cpu": "[json('0.25')]"}}`,
+			changed: true,
+		},
+		{
+			name:    "arm number",
+			content: `{"name": "checkout", "properties": {"scale": {"minReplicas": 3}}}`,
+			key:     "minReplicas",
+			value:   "1",
+			want:    `{"name": "checkout", "properties": {"scale": {"minReplicas": 1}}}`,
+			changed: true,
+		},
+		{
+			name:    "yaml scalar",
+			content: "name: checkout\nproperties:\n  template:\n    scale:\n      minReplicas: 2\n",
+			key:     "minReplicas",
+			value:   "0",
+			want:    "name: checkout\nproperties:\n  template:\n    scale:\n      minReplicas: 0\n",
+			changed: true,
+		},
+		{
+			// "checkout-v2" must not match the checkout app.
+			name:    "name prefix",
+			content: "name: checkout-v2\nminReplicas: 2\n",
+			key:     "minReplicas",
+			value:   "0",
+		},
+		{
+			name:     "vmSize after the name",
+			content:  "name: 'vm-a'\nvmSize: 'Standard_D4s_v5'\n---\nname: 'vm-b'\nvmSize: 'Standard_D8s_v5'\n",
+			resource: "vm-b",
+			key:      "vmSize",
+			value:    "Standard_D2s_v5",
+			want:     "name: 'vm-a'\nvmSize: 'Standard_D4s_v5'\n---\nname: 'vm-b'\nvmSize: 'Standard_D2s_v5'\n",
+			changed:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource := tt.resource
+			if resource == "" {
+				resource = "checkout"
+			}
+			got, changed := setAzureProperty(tt.content, resource, tt.key, tt.value)
+			if changed != tt.changed {
+				t.Fatalf("changed = %v, want %v", changed, tt.changed)
+			}
+			if changed && got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAzureTemplateValue(t *testing.T) {
+	tests := []struct {
+		op   types.FixOperation
+		want string
+	}{
+		{types.FixOperation{Target: "Standard_D2s_v5", Value: 2}, "Standard_D2s_v5"},
+		{types.FixOperation{Value: 1.5, Unit: "Gi"}, "1.5Gi"},
+		{types.FixOperation{Value: 0.25, Unit: "cores"}, "0.25"},
+	}
+	for _, tt := range tests {
+		if got := azureTemplateValue(types.FixAction{Action: tt.op}); got != tt.want {
+			t.Errorf("azureTemplateValue(%+v) = %q, want %q", tt.op, got, tt.want)
+		}
+	}
+}
+
+func TestApplyAzureFixFallsBackToTerraform(t *testing.T) {
+	t.Chdir(t.TempDir())
+	content := `resource "azurerm_linux_virtual_machine" "api" {
+  name = "api"
+  size = "Standard_D8s_v5"
+}
+`
+	if err := os.WriteFile("vm.tf", []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := applyFix(types.FixAction{
+		Provider: types.ProviderAzureVM,
+		Resource: "api",
+		Action:   types.FixOperation{Field: "vmSize", Target: "Standard_D2s_v5"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw, _ := os.ReadFile("vm.tf")
+	want := `resource "azurerm_linux_virtual_machine" "api" {
+  name = "api"
+  size = "Standard_D2s_v5"
+}
+`
+	if string(raw) != want {
+		t.Errorf("got\n%s\nwant\n%s", raw, want)
+	}
+}
@@ -0,0 +1,181 @@
+package fix
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+// applyECSFix sets the task-level cpu and memory of the task definition whose
+// family is the action's resource. The target is "<cpu units>/<MiB>".
+func applyECSFix(action types.FixAction) error {
+	cpu, memory, ok := strings.Cut(action.Action.Target, "/")
+	if action.Action.Field != "task_size" || !ok {
+		return fmt.Errorf("unsupported ECS fix: %s=%s", action.Action.Field, action.Action.Target)
+	}
+
+	files, err := findTaskDefinitions()
+	if err != nil {
+		return fmt.Errorf("failed to find task definitions: %w", err)
+	}
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+
+		updated := string(content)
+		var changed bool
+		if filepath.Ext(file) == ".tf" {
+			resourceTypes := []string{"aws_ecs_task_definition"}
+			if updated, changed = setTerraformAttribute(updated, resourceTypes, action.Resource, "cpu", fmt.Sprintf("%q", cpu)); changed {
+				updated, changed = setTerraformAttribute(updated, resourceTypes, action.Resource, "memory", fmt.Sprintf("%q", memory))
+			}
+		} else if updated, changed = setTaskDefinitionField(updated, action.Resource, "cpu", cpu); changed {
+			updated, changed = setTaskDefinitionField(updated, action.Resource, "memory", memory)
+		}
+
+		if changed {
+			return os.WriteFile(file, []byte(updated), 0644)
+		}
+	}
+
+	return fmt.Errorf("no task definition found for family %s", action.Resource)
+}
+
+func findTaskDefinitions() ([]string, error) {
+	var files []string
+
+	err := filepath.Walk(".", func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if info.IsDir() {
+			if name := info.Name(); name == ".git" || name == ".terraform" || name == "node_modules" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		switch filepath.Ext(p) {
+		case ".tf":
+			files = append(files, p)
+		case ".json":
+			content, err := os.ReadFile(p)
+			if err == nil && strings.Contains(string(content), `"containerDefinitions"`) {
+				files = append(files, p)
+			}
+		}
+		return nil
+	})
+
+	return files, err
+}
+
+// jsonKey is a key of a JSON object along with the span of its scalar value.
+type jsonKey struct {
+	name       string
+	object     int
+	valueStart int
+	valueEnd   int
+}
+
+// setTaskDefinitionField rewrites key in the JSON object that declares
+// "family": family, leaving the same key inside containerDefinitions alone.
+// Numbers stay numbers and strings stay strings.
+func setTaskDefinitionField(content, family, key, value string) (string, bool) {
+	keys := scanJSONKeys(content)
+
+	object := -1
+	for _, k := range keys {
+		if k.name == "family" && strings.Trim(content[k.valueStart:k.valueEnd], `"`) == family {
+			object = k.object
+			break
+		}
+	}
+	if object < 0 {
+		return content, false
+	}
+
+	for _, k := range keys {
+		if k.object != object || k.name != key {
+			continue
+		}
+		if strings.HasPrefix(content[k.valueStart:k.valueEnd], `"`) {
+			value = fmt.Sprintf("%q", value)
+		}
+		return content[:k.valueStart] + value + content[k.valueEnd:], true
+	}
+
+	return content, false
+}
+
+// scanJSONKeys walks content once and records every object key with a
+// scalar value, tagged by the offset of the object that holds it.
+func scanJSONKeys(content string) []jsonKey {
+	var keys []jsonKey
+	var objects []int
+
+	stringEnd := func(i int) int {
+		for j := i + 1; j < len(content); j++ {
+			switch content[j] {
+			case '\\':
+				j++
+			case '"':
+				return j + 1
+			}
+		}
+		return len(content)
+	}
+
+	for i := 0; i < len(content); i++ {
+		switch content[i] {
+		case '{':
+			objects = append(objects, i)
+		case '}':
+			if len(objects) > 0 {
+				objects = objects[:len(objects)-1]
+			}
+		case '"':
+			end := stringEnd(i)
+			name := content[i+1 : end-1]
+
+			j := end
+			for j < len(content) && strings.ContainsRune(" \t\r\n", rune(content[j])) {
+				j++
+			}
+			if j >= len(content) || content[j] != ':' || len(objects) == 0 {
+				i = end - 1
+				continue
+			}
+
+			j++
+			for j < len(content) && strings.ContainsRune(" \t\r\n", rune(content[j])) {
+				j++
+			}
+			if j >= len(content) || content[j] == '{' || content[j] == '[' {
+				i = j - 1
+				continue
+			}
+
+			valueEnd := j
+			if content[j] == '"' {
+				valueEnd = stringEnd(j)
+			} else {
+				for valueEnd < len(content) && !strings.ContainsRune(",}] \t\r\n", rune(content[valueEnd])) {
+					valueEnd++
+				}
+			}
+
+			keys = append(keys, jsonKey{name: name, object: objects[len(objects)-1], valueStart: j, valueEnd: valueEnd})
+			i = valueEnd - 1
+		}
+	}
+
+	return keys
+}
@@ -0,0 +1,170 @@
+package fix
+
+import (
+	"os"
+	"testing"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+const taskDefinition = `{
+  "family": "checkout",
+  "cpu": "1024",
+  "memory": "4096",
+  "containerDefinitions": [
+    {
+      "name": "app",
+      "cpu": 512,
+      "memory": 2048,
+      "environment": [{"name": "MODE", "value": "cpu: \"fast\""}]
+    }
+  ]
+}
+`
+
+func TestSetTaskDefinitionField(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		family  string
+		key     string
+		want    string
+		changed bool
+	}{
+		{
+			name:    "string value stays a string",
+			content: taskDefinition,
+			family:  "checkout",
+			key:     "cpu",
+			want: `{
+  "family": "checkout",
+  "cpu": "256",
+  "memory": "4096",
+  "containerDefinitions": [
+    {
+      "name": "app",
+      "cpu": 512,
+      "memory": 2048,
+      "environment": [{"name": "MODE", "value": "cpu: \"fast\""}]
+    }
+  ]
+}
+`,
+			changed: true,
+		},
+		{
+			name:    "number stays a number",
+			content: `{"containerDefinitions": [{"cpu": 10}], "family": "checkout", "cpu": 1024}`,
+			family:  "checkout",
+			key:     "cpu",
+			want:    `{"containerDefinitions": [{"cpu": 10}], "family": "checkout", "cpu": 256}`,
+			changed: true,
+		},
+		{
+			name:    "other family",
+			content: taskDefinition,
+			family:  "payments",
+			key:     "cpu",
+		},
+		{
+			name:    "key only in containers",
+			content: taskDefinition,
+			family:  "checkout",
+			key:     "name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := setTaskDefinitionField(tt.content, tt.family, tt.key, "256")
+			if changed != tt.changed {
+				t.Fatalf("changed = %v, want %v", changed, tt.changed)
+			}
+			if !changed {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyECSFix(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    string
+	}{
+		{
+			name:    "task definition JSON",
+			file:    "taskdef.json",
+			content: taskDefinition,
+			want: `{
+  "family": "checkout",
+  "cpu": "512",
+  "memory": "1024",
+  "containerDefinitions": [
+    {
+      "name": "app",
+      "cpu": 512,
+      "memory": 2048,
+      "environment": [{"name": "MODE", "value": "cpu: \"fast\""}]
+    }
+  ]
+}
+`,
+		},
+		{
+			name: "terraform",
+			file: "ecs.tf",
+			content: `resource "aws_ecs_task_definition" "checkout" {
+  family = "checkout"
+  cpu    = "1024"
+  memory = "4096"
+}
+`,
+			want: `resource "aws_ecs_task_definition" "checkout" {
+  family = "checkout"
+  cpu    = "512"
+  memory = "1024"
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			if err := os.WriteFile(tt.file, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			err := applyFix(types.FixAction{
+				Provider: types.ProviderAWSECSFargate,
+				Resource: "checkout",
+				Action:   types.FixOperation{Field: "task_size", Target: "512/1024"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			raw, _ := os.ReadFile(tt.file)
+			if string(raw) != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", raw, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyECSFixRejectsBadTarget(t *testing.T) {
+	err := applyFix(types.FixAction{
+		Provider: types.ProviderAWSECSFargate,
+		Resource: "checkout",
+		Action:   types.FixOperation{Field: "task_size", Target: "512"},
+	})
+	if err == nil {
+		t.Error("expected an error for a target without memory")
+	}
+}
@@ -0,0 +1,159 @@
+package fix
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+const lifecycleDocDir = "s3-lifecycle"
+
+// applyLifecycleFix adds the action's rule to the bucket's Terraform
+// aws_s3_bucket_lifecycle_configuration, creating that resource next to the
+// aws_s3_bucket when it does not exist yet. Without Terraform for the bucket
+// it writes a JSON lifecycle document under s3-lifecycle/ instead, ready for
+// `aws s3api put-bucket-lifecycle-configuration`.
+func applyLifecycleFix(action types.FixAction) error {
+	if action.Action.Operation != "add_lifecycle_rule" {
+		return fmt.Errorf("unsupported lifecycle operation: %s", action.Action.Operation)
+	}
+
+	if action.Provider == types.ProviderAWSS3 {
+		files, err := findTerraformFiles()
+		if err != nil {
+			return fmt.Errorf("failed to find Terraform files: %w", err)
+		}
+
+		for _, file := range files {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				continue
+			}
+
+			updated, ok := addTerraformLifecycleRule(string(content), action)
+			if ok {
+				return os.WriteFile(file, []byte(updated), 0644)
+			}
+		}
+	}
+
+	return writeLifecycleDocument(action)
+}
+
+// addTerraformLifecycleRule appends a rule block to the lifecycle
+// configuration of the bucket, or adds a new configuration after the
+// bucket's aws_s3_bucket block.
+func addTerraformLifecycleRule(content string, action types.FixAction) (string, bool) {
+	label := ""
+	bucketEnd := -1
+	namePattern := regexp.MustCompile(`(?m)^\s*bucket\s*=\s*"` + regexp.QuoteMeta(action.Resource) + `"`)
+
+	for _, loc := range terraformBlockPattern.FindAllStringSubmatchIndex(content, -1) {
+		if content[loc[2]:loc[3]] != "aws_s3_bucket" {
+			continue
+		}
+		start := loc[1]
+		end := blockEnd(content, start)
+		if l := content[loc[4]:loc[5]]; l == action.Resource || namePattern.MatchString(content[start:end]) {
+			label, bucketEnd = l, end
+			break
+		}
+	}
+	if label == "" {
+		return content, false
+	}
+
+	rule := terraformLifecycleRule(action, "  ")
+
+	refPattern := regexp.MustCompile(`(?m)^\s*bucket\s*=\s*(aws_s3_bucket\.` + regexp.QuoteMeta(label) + `\.(id|bucket)|"` + regexp.QuoteMeta(action.Resource) + `")\s*$`)
+	for _, loc := range terraformBlockPattern.FindAllStringSubmatchIndex(content, -1) {
+		if content[loc[2]:loc[3]] != "aws_s3_bucket_lifecycle_configuration" {
+			continue
+		}
+		start := loc[1]
+		end := blockEnd(content, start)
+		block := content[start:end]
+		if !refPattern.MatchString(block) {
+			continue
+		}
+		if strings.Contains(block, `"`+action.Action.Target+`"`) {
+			return content, true
+		}
+		return content[:end] + "\n" + rule + content[end:], true
+	}
+
+	config := fmt.Sprintf("\n\nresource \"aws_s3_bucket_lifecycle_configuration\" %q {\n  bucket = aws_s3_bucket.%s.id\n\n%s}",
+		label, label, rule)
+	return content[:bucketEnd+1] + config + content[bucketEnd+1:], true
+}
+
+func terraformLifecycleRule(action types.FixAction, indent string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%srule {\n", indent)
+	fmt.Fprintf(&b, "%s  id     = %q\n", indent, action.Action.Target)
+	fmt.Fprintf(&b, "%s  status = \"Enabled\"\n\n", indent)
+	fmt.Fprintf(&b, "%s  filter {}\n\n", indent)
+
+	if class, ok := strings.CutPrefix(action.Action.Field, "lifecycle.transition."); ok {
+		fmt.Fprintf(&b, "%s  transition {\n", indent)
+		fmt.Fprintf(&b, "%s    days          = %g\n", indent, action.Action.Value)
+		fmt.Fprintf(&b, "%s    storage_class = %q\n", indent, class)
+	} else {
+		fmt.Fprintf(&b, "%s  abort_incomplete_multipart_upload {\n", indent)
+		fmt.Fprintf(&b, "%s    days_after_initiation = %g\n", indent, action.Action.Value)
+	}
+	fmt.Fprintf(&b, "%s  }\n", indent)
+	fmt.Fprintf(&b, "%s}\n", indent)
+	return b.String()
+}
+
+// writeLifecycleDocument merges the rule into s3-lifecycle/<bucket>.json,
+// replacing any rule with the same ID.
+func writeLifecycleDocument(action types.FixAction) error {
+	path := filepath.Join(lifecycleDocDir, action.Resource+".json")
+
+	doc := struct {
+		Rules []map[string]any `json:"Rules"`
+	}{}
+	if raw, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			return fmt.Errorf("invalid lifecycle document %s: %w", path, err)
+		}
+	}
+
+	rule := map[string]any{
+		"ID":     action.Action.Target,
+		"Status": "Enabled",
+		"Filter": map[string]any{},
+	}
+	if class, ok := strings.CutPrefix(action.Action.Field, "lifecycle.transition."); ok {
+		rule["Transitions"] = []map[string]any{{"Days": action.Action.Value, "StorageClass": class}}
+	} else {
+		rule["AbortIncompleteMultipartUpload"] = map[string]any{"DaysAfterInitiation": action.Action.Value}
+	}
+
+	replaced := false
+	for i, r := range doc.Rules {
+		if r["ID"] == action.Action.Target {
+			doc.Rules[i], replaced = rule, true
+		}
+	}
+	if !replaced {
+		doc.Rules = append(doc.Rules, rule)
+	}
+
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(lifecycleDocDir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(out, '\n'), 0644)
+}
@@ -0,0 +1,237 @@
+package fix
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+var terraformBlockPattern = regexp.MustCompile(`(?m)^\s*resource\s+"([^"]+)"\s+"([^"]+)"\s*\{`)
+
+// applyTerraformFix sets an attribute on the Terraform resource of one of
+// resourceTypes whose label or name attribute matches the action's
+// resource. The attribute is taken from the action's field, and the new value
+// from its target (or numeric value when there is no target).
+func applyTerraformFix(action types.FixAction, resourceTypes []string) error {
+	files, err := findTerraformFiles()
+	if err != nil {
+		return fmt.Errorf("failed to find Terraform files: %w", err)
+	}
+
+	if len(files) == 0 {
+		return fmt.Errorf("no Terraform files found")
+	}
+
+	value := fmt.Sprintf("%q", action.Action.Target)
+	if action.Action.Target == "" {
+		value = fmt.Sprintf("%g", action.Action.Value)
+	}
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+
+		updated, ok := setTerraformAttribute(string(content), resourceTypes, action.Resource, action.Action.Field, value)
+		if ok {
+			return os.WriteFile(file, []byte(updated), 0644)
+		}
+	}
+
+	return fmt.Errorf("no %s resource named %s sets %s", strings.Join(resourceTypes, "/"), action.Resource, action.Action.Field)
+}
+
+// applyRDSFix edits the aws_db_instance. A storage type change also drops
+// the iops attribute: the recommended type serves the observed IOPS from its
+// baseline, so keeping a provisioned figure would only keep paying for it.
+// Instances that leave storage_type unset (the gp2 default) get the
+// attribute added instead.
+func applyRDSFix(action types.FixAction) error {
+	resourceTypes := []string{"aws_db_instance"}
+	if action.Intent != "change_storage_type" {
+		return applyTerraformFix(action, resourceTypes)
+	}
+
+	files, err := findTerraformFiles()
+	if err != nil {
+		return fmt.Errorf("failed to find Terraform files: %w", err)
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no Terraform files found")
+	}
+
+	value := fmt.Sprintf("%q", action.Action.Target)
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+
+		updated, ok := setTerraformAttribute(string(content), resourceTypes, action.Resource, action.Action.Field, value)
+		if !ok {
+			updated, ok = insertTerraformAttribute(string(content), resourceTypes, action.Resource, action.Action.Field, value)
+		}
+		if !ok {
+			continue
+		}
+		updated, _ = removeTerraformAttribute(updated, resourceTypes, action.Resource, "iops")
+		return os.WriteFile(file, []byte(updated), 0644)
+	}
+
+	return fmt.Errorf("no aws_db_instance resource named %s", action.Resource)
+}
+
+func findTerraformFiles() ([]string, error) {
+	var files []string
+
+	err := filepath.Walk(".", func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if info.IsDir() {
+			if name := info.Name(); name == ".git" || name == ".terraform" || name == "node_modules" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if filepath.Ext(p) == ".tf" {
+			files = append(files, p)
+		}
+		return nil
+	})
+
+	return files, err
+}
+
+// setTerraformAttribute rewrites attr inside the first matching resource
+// block. Blocks are delimited by brace counting, which is enough for the
+// flat attribute layouts CostGuard edits.
+func setTerraformAttribute(content string, resourceTypes []string, name, attr, value string) (string, bool) {
+	attrPattern := regexp.MustCompile(`(?m)^(\s*)` + regexp.QuoteMeta(attr) + `(\s*)=\s*("[^"]*"|[^\s#]+)`)
+	namePattern := regexp.MustCompile(`(?m)^\s*(name|identifier|family|bucket)\s*=\s*"` + regexp.QuoteMeta(name) + `"`)
+
+	for _, loc := range terraformBlockPattern.FindAllStringSubmatchIndex(content, -1) {
+		blockType := content[loc[2]:loc[3]]
+		label := content[loc[4]:loc[5]]
+
+		if !containsString(resourceTypes, blockType) {
+			continue
+		}
+
+		start := loc[1]
+		end := blockEnd(content, start)
+		block := content[start:end]
+
+		if label != name && !namePattern.MatchString(block) {
+			continue
+		}
+
+		attrLoc := attrPattern.FindStringSubmatchIndex(block)
+		if attrLoc == nil {
+			continue
+		}
+
+		replaced := block[:attrLoc[0]] +
+			block[attrLoc[2]:attrLoc[3]] + attr + block[attrLoc[4]:attrLoc[5]] + "= " + value +
+			block[attrLoc[1]:]
+
+		return content[:start] + replaced + content[end:], true
+	}
+
+	return content, false
+}
+
+// removeTerraformAttribute deletes the line setting attr inside the first
+// matching resource block that has it.
+func removeTerraformAttribute(content string, resourceTypes []string, name, attr string) (string, bool) {
+	attrPattern := regexp.MustCompile(`(?m)^[ \t]*` + regexp.QuoteMeta(attr) + `\s*=.*\n?`)
+	namePattern := regexp.MustCompile(`(?m)^\s*(name|identifier|family|bucket)\s*=\s*"` + regexp.QuoteMeta(name) + `"`)
+
+	for _, loc := range terraformBlockPattern.FindAllStringSubmatchIndex(content, -1) {
+		if !containsString(resourceTypes, content[loc[2]:loc[3]]) {
+			continue
+		}
+
+		start := loc[1]
+		end := blockEnd(content, start)
+		block := content[start:end]
+
+		if content[loc[4]:loc[5]] != name && !namePattern.MatchString(block) {
+			continue
+		}
+
+		if attrLoc := attrPattern.FindStringIndex(block); attrLoc != nil {
+			return content[:start] + block[:attrLoc[0]] + block[attrLoc[1]:] + content[end:], true
+		}
+	}
+
+	return content, false
+}
+
+// insertTerraformAttribute adds attr as the first line of the first matching
+// resource block, indented like the block's existing attributes.
+func insertTerraformAttribute(content string, resourceTypes []string, name, attr, value string) (string, bool) {
+	indentPattern := regexp.MustCompile(`(?m)^([ \t]+)\S`)
+	namePattern := regexp.MustCompile(`(?m)^\s*(name|identifier|family|bucket)\s*=\s*"` + regexp.QuoteMeta(name) + `"`)
+
+	for _, loc := range terraformBlockPattern.FindAllStringSubmatchIndex(content, -1) {
+		if !containsString(resourceTypes, content[loc[2]:loc[3]]) {
+			continue
+		}
+
+		start := loc[1]
+		end := blockEnd(content, start)
+		block := content[start:end]
+
+		if content[loc[4]:loc[5]] != name && !namePattern.MatchString(block) {
+			continue
+		}
+
+		indent := "  "
+		if m := indentPattern.FindStringSubmatch(block); m != nil {
+			indent = m[1]
+		}
+
+		line := indent + attr + " = " + value + "\n"
+		if nl := strings.IndexByte(block, '\n'); nl >= 0 {
+			return content[:start+nl+1] + line + content[start+nl+1:], true
+		}
+		return content[:start] + "\n" + line + content[start:], true
+	}
+
+	return content, false
+}
+
+// blockEnd returns the index of the closing brace of the block whose opening
+// brace ends right before start.
+func blockEnd(content string, start int) int {
+	depth := 1
+	for i := start; i < len(content); i++ {
+		switch content[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(content)
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
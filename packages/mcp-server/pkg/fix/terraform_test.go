@@ -0,0 +1,270 @@
+package fix
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+const gceConfig = `resource "google_compute_instance" "web" {
+  name         = "web-prod"
+  machine_type = "e2-standard-8"
+
+  boot_disk {
+    initialize_params {
+      image = "debian-12"
+    }
+  }
+}
+
+resource "google_compute_instance" "batch" {
+  machine_type = "e2-standard-8" # nightly jobs
+}
+
+resource "google_compute_disk" "web" {
+  machine_type = "not-a-machine"
+}
+`
+
+func TestSetTerraformAttribute(t *testing.T) {
+	gce := []string{"google_compute_instance"}
+
+	tests := []struct {
+		name    string
+		types   []string
+		target  string
+		attr    string
+		want    string
+		changed bool
+	}{
+		{
+			name:   "by name attribute",
+			types:  gce,
+			target: "web-prod",
+			attr:   "machine_type",
+			want: `resource "google_compute_instance" "web" {
+  name         = "web-prod"
+  machine_type = "e2-standard-2"
+`,
+			changed: true,
+		},
+		{
+			name:   "by label, keeping the comment",
+			types:  gce,
+			target: "batch",
+			attr:   "machine_type",
+			want: `resource "google_compute_instance" "batch" {
+  machine_type = "e2-standard-2" # nightly jobs
+}`,
+			changed: true,
+		},
+		{
+			name:   "other resource types are left alone",
+			types:  []string{"google_compute_disk"},
+			target: "web-prod",
+			attr:   "machine_type",
+		},
+		{
+			name:   "missing attribute",
+			types:  gce,
+			target: "batch",
+			attr:   "min_cpu_platform",
+		},
+		{
+			name:   "unknown resource",
+			types:  gce,
+			target: "api",
+			attr:   "machine_type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := setTerraformAttribute(gceConfig, tt.types, tt.target, tt.attr, `"e2-standard-2"`)
+			if changed != tt.changed {
+				t.Fatalf("changed = %v, want %v", changed, tt.changed)
+			}
+			if !changed {
+				if got != gceConfig {
+					t.Error("content modified without a match")
+				}
+				return
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("got\n%s\nwant it to contain\n%s", got, tt.want)
+			}
+			if n := strings.Count(got, `"e2-standard-8"`); n != 1 {
+				t.Errorf("%d e2-standard-8 left, want only the other instance untouched", n)
+			}
+		})
+	}
+}
+
+func TestRemoveTerraformAttribute(t *testing.T) {
+	content := `resource "aws_db_instance" "orders" {
+  identifier   = "orders"
+  storage_type = "io1"
+  iops         = 3000
+}
+
+resource "aws_db_instance" "users" {
+  iops = 1000
+}
+`
+	got, ok := removeTerraformAttribute(content, []string{"aws_db_instance"}, "orders", "iops")
+	if !ok {
+		t.Fatal("iops not removed")
+	}
+	want := `resource "aws_db_instance" "orders" {
+  identifier   = "orders"
+  storage_type = "io1"
+}
+
+resource "aws_db_instance" "users" {
+  iops = 1000
+}
+`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	if _, ok := removeTerraformAttribute(want, []string{"aws_db_instance"}, "orders", "iops"); ok {
+		t.Error("removed an attribute that is not set")
+	}
+}
+
+func TestApplyTerraformFix(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.MkdirAll("infra", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join("infra", "main.tf"), []byte(gceConfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	action := types.FixAction{
+		Provider: types.ProviderGCPGCE,
+		Resource: "batch",
+		Action:   types.FixOperation{Field: "machine_type", Target: "e2-standard-4"},
+	}
+	if err := applyFix(action); err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := os.ReadFile(filepath.Join("infra", "main.tf"))
+	if !strings.Contains(string(raw), `machine_type = "e2-standard-4" # nightly jobs`) {
+		t.Errorf("machine type not updated:\n%s", raw)
+	}
+
+	action.Resource = "missing"
+	if err := applyFix(action); err == nil {
+		t.Error("expected an error for an unknown instance")
+	}
+}
+
+func TestApplyRDSFix(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		action  types.FixAction
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "change storage type drops iops",
+			content: `resource "aws_db_instance" "orders" {
+  identifier     = "orders"
+  instance_class = "db.m5.large"
+  storage_type   = "io1"
+  iops           = 3000
+}
+`,
+			action: types.FixAction{
+				Intent: "change_storage_type",
+				Action: types.FixOperation{Field: "storage_type", Target: "gp3"},
+			},
+			want: `resource "aws_db_instance" "orders" {
+  identifier     = "orders"
+  instance_class = "db.m5.large"
+  storage_type   = "gp3"
+}
+`,
+		},
+		{
+			name: "default storage type is added",
+			content: `resource "aws_db_instance" "orders" {
+    identifier        = "orders"
+    allocated_storage = 100
+}
+`,
+			action: types.FixAction{
+				Intent: "change_storage_type",
+				Action: types.FixOperation{Field: "storage_type", Target: "gp3"},
+			},
+			want: `resource "aws_db_instance" "orders" {
+    storage_type = "gp3"
+    identifier        = "orders"
+    allocated_storage = 100
+}
+`,
+		},
+		{
+			name: "instance class",
+			content: `resource "aws_db_instance" "orders" {
+  instance_class = "db.m5.large"
+  iops           = 3000
+}
+`,
+			action: types.FixAction{
+				Intent: "rightsize_instance_class",
+				Action: types.FixOperation{Field: "instance_class", Target: "db.t3.large"},
+			},
+			want: `resource "aws_db_instance" "orders" {
+  instance_class = "db.t3.large"
+  iops           = 3000
+}
+`,
+		},
+		{
+			name: "unknown instance",
+			content: `resource "aws_db_instance" "users" {
+  storage_type = "io1"
+}
+`,
+			action: types.FixAction{
+				Intent: "change_storage_type",
+				Action: types.FixOperation{Field: "storage_type", Target: "gp3"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			if err := os.WriteFile("db.tf", []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			tt.action.Provider = types.ProviderAWSRDS
+			tt.action.Resource = "orders"
+			err := applyFix(tt.action)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			raw, _ := os.ReadFile("db.tf")
+			if string(raw) != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", raw, tt.want)
+			}
+		})
+	}
+}
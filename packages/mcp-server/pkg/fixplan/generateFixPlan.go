@@ -3,43 +3,120 @@ package fixplan
 import (
 	"fmt"
 
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/events"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/provider/aws"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/provider/azure"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/provider/gcp"
 	"github.com/tanay13/costguard/packages/mcp-server/pkg/provider/kubernetes"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/provider/network"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/provider/objectstorage"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/provider/storage"
 	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/utils"
 )
 
-func GenerateFixPlan(req types.FixPlanRequest) types.FixPlanResponse {
+func GenerateFixPlan(req types.FixPlanRequest) (types.FixPlanResponse, error) {
+	plan, err := generateFixPlan(req)
+	if err != nil {
+		events.EmitError("fixplan", "", err)
+		return plan, err
+	}
+
+	events.Emit(events.Event{
+		Type:    events.FixPlanGenerated,
+		Source:  "fixplan",
+		Message: plan.Summary,
+		Payload: struct {
+			Actions          int      `json:"actions"`
+			TotalSavingsUSD  float64  `json:"total_savings_usd"`
+			MeetsBudget      bool     `json:"meets_budget"`
+			RequiresApproval bool     `json:"requires_approval"`
+			Held             []string `json:"held,omitempty"`
+		}{len(plan.Actions), plan.TotalSavings, plan.MeetsBudget, plan.RequiresApproval, plan.Held},
+	})
+	return plan, nil
+}
+
+func generateFixPlan(req types.FixPlanRequest) (types.FixPlanResponse, error) {
+	// Without an explicit recommender the plan sticks to the values the scan
+	// already recommended.
+	var rec kubernetes.Recommender
+	if req.Recommender != "" {
+		var err error
+		if rec, err = kubernetes.NewRecommender(req.Recommender); err != nil {
+			return types.FixPlanResponse{}, err
+		}
+	}
+
 	totalCurrent := 0.0
-	totalOptimal := 0.0
 	actions := []types.FixAction{}
+	held := []string{}
+	claimTemplates := []types.AggregatedMetrics{}
+	flowSources := []types.AggregatedMetrics{}
 
 	for _, agg := range req.AggregatedMetrics {
 
 		totalCurrent += agg.CostCurrentUSD
-		totalOptimal += agg.CostOptimalUSD
+
+		// Rightsizing on usage recorded mid-incident would bake the
+		// incident into the new requests.
+		if hasOngoingAnomaly(agg) {
+			held = append(held, agg.Resource)
+			continue
+		}
+		if agg.Provider == types.ProviderKubernetesStorage {
+			claimTemplates = append(claimTemplates, agg)
+		}
+		if agg.Network != nil {
+			flowSources = append(flowSources, agg)
+		}
 
 		switch agg.Provider {
 		case types.ProviderKubernetes:
-			kActions := kubernetes.GenerateK8sFixActions(agg)
+			actions = append(actions, kubernetes.GenerateK8sFixActions(agg, rec)...)
+		case types.ProviderKubernetesStorage:
+			actions = append(actions, storage.GenerateStorageFixActions(agg)...)
+		case types.ProviderGCPCloudRun:
+			actions = append(actions, gcp.GenerateCloudRunFixActions(agg)...)
+		case types.ProviderGCPGCE:
+			actions = append(actions, gcp.GenerateGCEFixActions(agg)...)
+		case types.ProviderAzureContainerApps:
+			actions = append(actions, azure.GenerateContainerAppsFixActions(agg)...)
+		case types.ProviderAzureVM:
+			actions = append(actions, azure.GenerateVMFixActions(agg)...)
+		case types.ProviderAWSECSFargate:
+			actions = append(actions, aws.GenerateFargateFixActions(agg)...)
+		case types.ProviderAWSRDS:
+			actions = append(actions, aws.GenerateRDSFixActions(agg)...)
+		case types.ProviderAWSS3, types.ProviderObjectStorage:
+			actions = append(actions, objectstorage.GenerateLifecycleFixActions(agg)...)
+		}
+	}
 
-			for i := range kActions {
+	actions = append(actions, storage.GenerateClaimTemplateFixActions(claimTemplates)...)
+	actions = append(actions, network.GenerateTopologyFixActions(flowSources)...)
 
-				if len(kActions) > 1 {
-					kActions[i].EstimatedSavingsUSD = agg.CostSavingsUSD / float64(len(kActions))
-				} else {
-					kActions[i].EstimatedSavingsUSD = agg.CostSavingsUSD
-				}
-			}
-			actions = append(actions, kActions...)
+	// Totals are reconciled from the actions themselves so the plan never
+	// promises savings that no action would deliver.
+	totalSavings := 0.0
+	requiresApproval := !req.AutoApprove
+	for i := range actions {
+		if actions[i].ID == "" {
+			actions[i].ID = utils.ActionID(actions[i])
 		}
-
+		totalSavings += actions[i].EstimatedSavingsUSD
+		requiresApproval = requiresApproval || actions[i].RequiresApproval
 	}
 
-	totalSavings := totalCurrent - totalOptimal
+	totalOptimal := totalCurrent - totalSavings
 
 	summary := fmt.Sprintf(
 		"Total cost: $%.2f → optimized: $%.2f (savings: $%.2f)",
 		totalCurrent, totalOptimal, totalSavings,
 	)
+	if len(held) > 0 {
+		summary += fmt.Sprintf("; %d resource(s) held for ongoing usage anomalies", len(held))
+	}
 
 	return types.FixPlanResponse{
 		TotalCurrentCost: totalCurrent,
@@ -47,8 +124,24 @@ func GenerateFixPlan(req types.FixPlanRequest) types.FixPlanResponse {
 		TotalSavings:     totalSavings,
 		BudgetTarget:     req.BudgetTarget,
 		MeetsBudget:      req.BudgetTarget > 0 && totalOptimal <= req.BudgetTarget,
-		RequiresApproval: !req.AutoApprove,
+		RequiresApproval: requiresApproval,
 		Actions:          actions,
+		Held:             held,
 		Summary:          summary,
+	}, nil
+}
+
+// hasOngoingAnomaly reports an ongoing rise in usage. Drops are not held:
+// the week of inactivity that makes a workload idle is itself a drop, and
+// idle workloads are exactly the ones to scale down.
+func hasOngoingAnomaly(agg types.AggregatedMetrics) bool {
+	if agg.Idle != nil {
+		return false
+	}
+	for _, a := range agg.Anomalies {
+		if a.Ongoing && a.Score > 0 {
+			return true
+		}
 	}
+	return false
 }
@@ -0,0 +1,143 @@
+package fixplan
+
+import (
+	"testing"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/scan"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/utils"
+)
+
+func k8sSamples(resource string, cpu, mem, replicas float64) []types.MetricCollection {
+	out := []types.MetricCollection{}
+	for i := 0; i < 50; i++ {
+		out = append(out, types.MetricCollection{
+			Resource:  resource,
+			Provider:  types.ProviderKubernetes,
+			TimeStamp: int64(1700000000 + i*3600),
+			Metrics: types.ResourceMetrics{K8sResourceMetrics: types.K8sResourceMetrics{
+				CpuMilli: cpu, MemoryGB: mem, Replicas: replicas,
+			}},
+		})
+	}
+	return out
+}
+
+func assertUniqueIDs(t *testing.T, actions []types.FixAction) {
+	t.Helper()
+	seen := map[string]string{}
+	for _, a := range actions {
+		if prev, ok := seen[a.ID]; ok {
+			t.Errorf("duplicate action ID %s: %s and %s/%s", a.ID, prev, a.Resource, a.Intent)
+		}
+		seen[a.ID] = a.Resource + "/" + a.Intent
+	}
+}
+
+func TestGenerateFixPlanTotals(t *testing.T) {
+	tests := []struct {
+		name        string
+		replicas    float64
+		autoscalers map[string]types.HPAConfig
+	}{
+		{"single pod", 0, nil},
+		{"static replicas", 10, nil},
+		{"hpa at min", 10, map[string]types.HPAConfig{
+			"api": {MinReplicas: 10, MaxReplicas: 100, TargetCPUUtilization: 30},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := scan.RunScan(types.ScanRequest{
+				Metrics:        k8sSamples("api", 50, 0.2, tt.replicas),
+				ActualRequests: map[string]types.Requests{"api": {CpuMilli: 1000, MemoryGB: 2}},
+				Autoscalers:    tt.autoscalers,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			plan, err := generateFixPlan(types.FixPlanRequest{
+				AggregatedMetrics: utils.ConvertScanToAggregated(res),
+				AutoApprove:       true,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(plan.Actions) == 0 {
+				t.Fatal("expected actions for an over-provisioned workload")
+			}
+			if plan.TotalOptimalCost < 0 {
+				t.Errorf("TotalOptimalCost = %.2f, want >= 0 (current %.2f, savings %.2f)",
+					plan.TotalOptimalCost, plan.TotalCurrentCost, plan.TotalSavings)
+			}
+			if plan.TotalSavings > plan.TotalCurrentCost {
+				t.Errorf("TotalSavings = %.2f exceeds TotalCurrentCost %.2f", plan.TotalSavings, plan.TotalCurrentCost)
+			}
+			assertUniqueIDs(t, plan.Actions)
+		})
+	}
+}
+
+func TestGenerateFixPlanTopologyActions(t *testing.T) {
+	flow := func(dest string, cost float64) *types.NetworkInfo {
+		return &types.NetworkInfo{
+			CrossZoneGB: 500,
+			CostUSD:     cost,
+			Flows: []types.NetworkFlow{
+				{Destination: dest, Kind: "cross_zone", MonthlyGB: 500, CostUSD: cost, TopologyAware: true},
+			},
+		}
+	}
+	aggs := []types.AggregatedMetrics{
+		{Provider: types.ProviderNetworkFlow, Resource: "web", Network: flow("db", 10), CostCurrentUSD: 10},
+		{Provider: types.ProviderNetworkFlow, Resource: "worker", Network: flow("db", 20), CostCurrentUSD: 20},
+		{Provider: types.ProviderNetworkFlow, Resource: "api", Network: flow("cache", 5), CostCurrentUSD: 5},
+	}
+
+	plan, err := generateFixPlan(types.FixPlanRequest{AggregatedMetrics: aggs})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertUniqueIDs(t, plan.Actions)
+
+	byTarget := map[string]types.FixAction{}
+	for _, a := range plan.Actions {
+		byTarget[a.Resource] = a
+	}
+	if len(plan.Actions) != 2 {
+		t.Fatalf("got %d actions, want one per destination: %+v", len(plan.Actions), plan.Actions)
+	}
+	if got := byTarget["db"].EstimatedSavingsUSD; got < 20.99 || got > 21.01 {
+		t.Errorf("db savings = %.2f, want 21.00 (30 × 0.7)", got)
+	}
+	if plan.TotalOptimalCost < 0 {
+		t.Errorf("TotalOptimalCost = %.2f, want >= 0", plan.TotalOptimalCost)
+	}
+}
+
+func TestHasOngoingAnomaly(t *testing.T) {
+	tests := []struct {
+		name string
+		agg  types.AggregatedMetrics
+		want bool
+	}{
+		{"none", types.AggregatedMetrics{}, false},
+		{"ongoing rise", types.AggregatedMetrics{Anomalies: []types.Anomaly{{Ongoing: true, Score: 5}}}, true},
+		{"ongoing drop", types.AggregatedMetrics{Anomalies: []types.Anomaly{{Ongoing: true, Score: -5}}}, false},
+		{"past rise", types.AggregatedMetrics{Anomalies: []types.Anomaly{{Score: 5}}}, false},
+		{"idle", types.AggregatedMetrics{
+			Idle:      &types.IdleReport{},
+			Anomalies: []types.Anomaly{{Ongoing: true, Score: 5}},
+		}, false},
+	}
+
+	for _, tt := range tests {
+		if got := hasOngoingAnomaly(tt.agg); got != tt.want {
+			t.Errorf("%s: hasOngoingAnomaly = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package forecast
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/history"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/pricing"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+const (
+	MaxHorizon = 3
+	confidence = 0.95
+
+	month = time.Duration(pricing.HoursPerMonth) * time.Hour
+)
+
+// Project forecasts total and per-resource monthly cost for the next
+// horizon months from the scans in history matching q. Months start at the
+// latest scan, and only resources still present in it are projected. Only
+// cost is forecast; usage is not fitted. The total is checked against
+// budget, or against the BudgetTarget of the latest fix plan matching q when
+// budget is zero.
+func Project(store *history.Store, q history.Query, horizon int, budget float64) (types.ForecastResponse, error) {
+	if horizon < 1 || horizon > MaxHorizon {
+		return types.ForecastResponse{}, fmt.Errorf("horizon must be between 1 and %d months", MaxHorizon)
+	}
+
+	if budget <= 0 {
+		plans, err := store.List(history.Query{
+			Kind:   history.KindFixPlan,
+			Remote: q.Remote,
+			Since:  q.Since,
+			Until:  q.Until,
+			Limit:  1,
+		})
+		if err == nil && len(plans) > 0 && plans[0].FixPlan != nil {
+			budget = plans[0].FixPlan.BudgetTarget
+		}
+	}
+
+	resp := types.ForecastResponse{
+		Horizon:      horizon,
+		Confidence:   confidence,
+		BudgetTarget: budget,
+		Resources:    []types.SeriesForecast{},
+	}
+
+	totals, err := store.CostSeries(q, history.GroupByTotal, "")
+	if err != nil {
+		return resp, err
+	}
+	if len(totals) < MinPoints {
+		return resp, fmt.Errorf("need at least %d scans in history to forecast, have %d", MinPoints, len(totals))
+	}
+	latest := totals[len(totals)-1]
+	start := latest.Timestamp
+
+	resp.Total, err = projectSeries(history.GroupByTotal, totals, start, horizon)
+	if err != nil {
+		return resp, err
+	}
+	if budget > 0 {
+		for i := range resp.Total.Months {
+			resp.Total.Months[i].Budget = budgetStatus(resp.Total.Months[i], budget)
+		}
+	}
+
+	points, err := store.CostSeries(q, history.GroupByResource, "")
+	if err != nil {
+		return resp, err
+	}
+	byResource := map[string][]history.CostPoint{}
+	for _, p := range points {
+		byResource[p.Key] = append(byResource[p.Key], p)
+	}
+
+	for key, series := range byResource {
+		if series[len(series)-1].ScanID != latest.ScanID {
+			continue
+		}
+		f, err := projectSeries(key, series, start, horizon)
+		if err != nil {
+			resp.Warnings = append(resp.Warnings, fmt.Sprintf("%s: %v", key, err))
+			continue
+		}
+		resp.Resources = append(resp.Resources, f)
+	}
+
+	sort.Slice(resp.Resources, func(i, j int) bool {
+		return resp.Resources[i].TrendUSDPerMonth > resp.Resources[j].TrendUSDPerMonth
+	})
+	sort.Strings(resp.Warnings)
+
+	return resp, nil
+}
+
+func projectSeries(key string, series []history.CostPoint, start time.Time, horizon int) (types.SeriesForecast, error) {
+	samples := make([]Sample, 0, len(series))
+	for _, p := range series {
+		samples = append(samples, Sample{Time: p.Timestamp, Value: p.CurrentCostUSD})
+	}
+
+	m, err := Fit(samples)
+	if err != nil {
+		return types.SeriesForecast{}, err
+	}
+
+	last := series[len(series)-1]
+	f := types.SeriesForecast{
+		Key:              key,
+		Provider:         last.Provider,
+		DataPoints:       len(series),
+		LastCostUSD:      last.CurrentCostUSD,
+		TrendUSDPerMonth: m.Slope * month.Hours() / 24,
+		Seasonal:         m.HasSeasonality,
+		Months:           []types.ForecastPoint{},
+	}
+
+	for i := 0; i < horizon; i++ {
+		from := start.Add(time.Duration(i) * month)
+		mean, lower, upper := m.Window(from, from.Add(month))
+		f.Months = append(f.Months, types.ForecastPoint{
+			Month:    i + 1,
+			Start:    from,
+			CostUSD:  max(mean, 0),
+			LowerUSD: max(lower, 0),
+			UpperUSD: max(upper, 0),
+		})
+	}
+
+	return f, nil
+}
+
+// budgetStatus is a breach when the expected cost exceeds budget and at
+// risk when only the upper bound does.
+func budgetStatus(p types.ForecastPoint, budget float64) string {
+	switch {
+	case p.CostUSD > budget:
+		return types.BudgetBreach
+	case p.UpperUSD > budget:
+		return types.BudgetAtRisk
+	default:
+		return types.BudgetOK
+	}
+}
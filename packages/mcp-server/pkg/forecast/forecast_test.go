@@ -0,0 +1,113 @@
+package forecast
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/history"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+func TestProjectBudgetFallback(t *testing.T) {
+	store, err := history.Open(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	add := func(e history.Entry) {
+		t.Helper()
+		if _, err := store.Add(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	team := history.Repo{Remote: "git@example.com:team/app.git"}
+	other := history.Repo{Remote: "git@example.com:other/app.git"}
+
+	for d := 0; d < 5; d++ {
+		add(history.Entry{
+			Kind:      history.KindScan,
+			Timestamp: origin.Add(time.Duration(d) * day),
+			Repo:      team,
+			Scan: &types.ScanResponse{
+				Summary: types.ScanSummary{TotalCurrentCostUSD: 100 + 10*float64(d)},
+			},
+		})
+	}
+	add(history.Entry{
+		Kind:      history.KindFixPlan,
+		Timestamp: origin.Add(5 * day),
+		Repo:      team,
+		FixPlan:   &types.FixPlanResponse{BudgetTarget: 1000},
+	})
+	// The newest plan overall belongs to another repository and must not
+	// set the budget of a forecast scoped to team.
+	add(history.Entry{
+		Kind:      history.KindFixPlan,
+		Timestamp: origin.Add(6 * day),
+		Repo:      other,
+		FixPlan:   &types.FixPlanResponse{BudgetTarget: 50},
+	})
+
+	tests := []struct {
+		name   string
+		q      history.Query
+		budget float64
+		want   float64
+	}{
+		{"explicit budget", history.Query{Remote: team.Remote}, 500, 500},
+		{"scoped to repository", history.Query{Remote: team.Remote}, 0, 1000},
+		{"unscoped", history.Query{}, 0, 50},
+		{"no plan in window", history.Query{Remote: team.Remote, Until: origin.Add(4 * day)}, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := Project(store, tt.q, 2, tt.budget)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.BudgetTarget != tt.want {
+				t.Errorf("BudgetTarget = %.2f, want %.2f", resp.BudgetTarget, tt.want)
+			}
+		})
+	}
+}
+
+func TestProjectTrend(t *testing.T) {
+	store, err := history.Open(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	for d := 0; d < 10; d++ {
+		_, err := store.Add(history.Entry{
+			Kind:      history.KindScan,
+			Timestamp: origin.Add(time.Duration(d) * day),
+			Scan: &types.ScanResponse{
+				Summary: types.ScanSummary{TotalCurrentCostUSD: 100 + float64(d)},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resp, err := Project(store, history.Query{}, 1, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Total.Months) != 1 {
+		t.Fatalf("got %d months, want 1", len(resp.Total.Months))
+	}
+	if got := resp.Total.Months[0]; got.CostUSD <= 109 || got.Budget != types.BudgetBreach {
+		t.Errorf("month 1 = $%.2f (%s), want above the last scan and over budget", got.CostUSD, got.Budget)
+	}
+
+	if _, err := Project(store, history.Query{}, MaxHorizon+1, 0); err == nil {
+		t.Error("expected an error for a horizon beyond MaxHorizon")
+	}
+}
@@ -0,0 +1,209 @@
+package forecast
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// MinPoints is the fewest observations a trend is fitted on.
+	MinPoints = 3
+	// Weekly seasonality needs two full weeks so each weekday is seen
+	// more than once on average.
+	minSeasonalDays = 14
+
+	// Backfitting the trend and weekday effects converges in a handful of
+	// rounds; this only bounds pathological inputs.
+	maxBackfitIterations = 100
+
+	day = 24 * time.Hour
+)
+
+// tQuantile95 holds two-sided 95% Student's t quantiles by degrees of
+// freedom, rounded down to the nearest listed dof. Beyond 60 the normal
+// quantile is close enough.
+var tQuantile95 = []struct {
+	dof int
+	t   float64
+}{
+	{1, 12.71}, {2, 4.30}, {3, 3.18}, {4, 2.78}, {5, 2.57}, {6, 2.45}, {7, 2.36},
+	{8, 2.31}, {9, 2.26}, {10, 2.23}, {15, 2.13}, {20, 2.09}, {30, 2.04},
+}
+
+func quantile(dof int) float64 {
+	if dof > 60 {
+		return 1.96
+	}
+	for i := len(tQuantile95) - 1; i >= 0; i-- {
+		if dof >= tQuantile95[i].dof {
+			return tQuantile95[i].t
+		}
+	}
+	return tQuantile95[0].t
+}
+
+type Sample struct {
+	Time  time.Time
+	Value float64
+}
+
+// Model is a linear trend over days plus an additive day-of-week effect,
+// fitted by least squares.
+type Model struct {
+	Origin    time.Time
+	Intercept float64
+	// Slope is the change per day.
+	Slope    float64
+	Seasonal [7]float64
+	// HasSeasonality is false when the history is too short for weekly
+	// effects, in which case Seasonal is all zero.
+	HasSeasonality bool
+
+	sigma float64
+	n     int
+	meanX float64
+	sxx   float64
+	dof   int
+}
+
+// Fit fits the model to samples, which need not be evenly spaced.
+func Fit(samples []Sample) (Model, error) {
+	n := len(samples)
+	if n < MinPoints {
+		return Model{}, fmt.Errorf("need at least %d points, have %d", MinPoints, n)
+	}
+
+	m := Model{Origin: samples[0].Time, n: n}
+	for _, s := range samples {
+		if s.Time.Before(m.Origin) {
+			m.Origin = s.Time
+		}
+	}
+
+	xs := make([]float64, n)
+	maxX := 0.0
+	for i, s := range samples {
+		xs[i] = s.Time.Sub(m.Origin).Hours() / 24
+		m.meanX += xs[i]
+		maxX = math.Max(maxX, xs[i])
+	}
+	m.meanX /= float64(n)
+	for _, x := range xs {
+		m.sxx += (x - m.meanX) * (x - m.meanX)
+	}
+
+	// The trend and the weekday effects are fitted in turn on what the
+	// other leaves unexplained until they settle, which converges to the
+	// joint least-squares fit. Fitting each only once lets a weekday that
+	// falls mostly at one end of the window tilt the slope.
+	seasonal := maxX >= minSeasonalDays
+	seen := 0
+	resid := make([]float64, n)
+	for iter := 0; iter < maxBackfitIterations; iter++ {
+		meanY, sxy := 0.0, 0.0
+		for _, s := range samples {
+			meanY += s.Value - m.Seasonal[s.Time.UTC().Weekday()]
+		}
+		meanY /= float64(n)
+		for i, s := range samples {
+			sxy += (xs[i] - m.meanX) * (s.Value - m.Seasonal[s.Time.UTC().Weekday()] - meanY)
+		}
+		if m.sxx > 0 {
+			m.Slope = sxy / m.sxx
+		}
+		m.Intercept = meanY - m.Slope*m.meanX
+
+		for i, s := range samples {
+			resid[i] = s.Value - (m.Intercept + m.Slope*xs[i])
+		}
+		if !seasonal {
+			break
+		}
+
+		prev := m.Seasonal
+		m.Seasonal, seen = weekdayEffects(samples, resid)
+		change := 0.0
+		for d := range m.Seasonal {
+			change = math.Max(change, math.Abs(m.Seasonal[d]-prev[d]))
+		}
+		if change < 1e-9 {
+			break
+		}
+	}
+
+	params := 2
+	if seasonal {
+		m.HasSeasonality = seen > 1
+		params += seen - 1
+	}
+
+	sse := 0.0
+	for i, s := range samples {
+		e := resid[i] - m.Seasonal[s.Time.UTC().Weekday()]
+		sse += e * e
+	}
+	m.dof = max(n-params, 1)
+	m.sigma = math.Sqrt(sse / float64(m.dof))
+
+	return m, nil
+}
+
+// weekdayEffects averages resid per UTC weekday, centred so the effects of
+// the weekdays present sum to zero. It also returns how many weekdays were
+// present.
+func weekdayEffects(samples []Sample, resid []float64) ([7]float64, int) {
+	var effects, sum [7]float64
+	var count [7]int
+	for i, s := range samples {
+		d := s.Time.UTC().Weekday()
+		sum[d] += resid[i]
+		count[d]++
+	}
+
+	seen, total := 0, 0.0
+	for d := range effects {
+		if count[d] > 0 {
+			effects[d] = sum[d] / float64(count[d])
+			total += effects[d]
+			seen++
+		}
+	}
+	for d := range effects {
+		if count[d] > 0 {
+			effects[d] -= total / float64(seen)
+		}
+	}
+	return effects, seen
+}
+
+// Predict returns the expected value at t and its 95% prediction interval.
+func (m Model) Predict(t time.Time) (mean, lower, upper float64) {
+	x := t.Sub(m.Origin).Hours() / 24
+	mean = m.Intercept + m.Slope*x + m.Seasonal[t.UTC().Weekday()]
+
+	v := 1 + 1/float64(m.n)
+	if m.sxx > 0 {
+		v += (x - m.meanX) * (x - m.meanX) / m.sxx
+	}
+	half := quantile(m.dof) * m.sigma * math.Sqrt(v)
+
+	return mean, mean - half, mean + half
+}
+
+// Window averages predictions over each day in [start, end), which is how a
+// monthly bill accumulates a daily rate.
+func (m Model) Window(start, end time.Time) (mean, lower, upper float64) {
+	days := 0
+	for t := start.Add(day / 2); t.Before(end); t = t.Add(day) {
+		mu, lo, hi := m.Predict(t)
+		mean += mu
+		lower += lo
+		upper += hi
+		days++
+	}
+	if days == 0 {
+		return m.Predict(start)
+	}
+	return mean / float64(days), lower / float64(days), upper / float64(days)
+}
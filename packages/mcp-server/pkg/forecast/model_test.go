@@ -0,0 +1,154 @@
+package forecast
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var origin = time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC) // a Monday
+
+func daily(days int, f func(d int, t time.Time) float64) []Sample {
+	out := make([]Sample, days)
+	for d := range out {
+		t := origin.Add(time.Duration(d) * day)
+		out[d] = Sample{Time: t, Value: f(d, t)}
+	}
+	return out
+}
+
+func TestFit(t *testing.T) {
+	weekend := func(t time.Time) bool {
+		return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+	}
+
+	tests := []struct {
+		name      string
+		samples   []Sample
+		slope     float64
+		anySlope  bool
+		seasonal  bool
+		wantError bool
+	}{
+		{
+			name:      "too few points",
+			samples:   daily(MinPoints-1, func(d int, _ time.Time) float64 { return 1 }),
+			wantError: true,
+		},
+		{
+			name:    "exact line",
+			samples: daily(10, func(d int, _ time.Time) float64 { return 10 + 2*float64(d) }),
+			slope:   2,
+		},
+		{
+			name:    "flat",
+			samples: daily(10, func(int, time.Time) float64 { return 42 }),
+		},
+		{
+			name: "weekly pattern",
+			samples: daily(28, func(d int, t time.Time) float64 {
+				v := 100 + 0.5*float64(d)
+				if weekend(t) {
+					v -= 20
+				}
+				return v
+			}),
+			slope:    0.5,
+			seasonal: true,
+		},
+		{
+			// Under two weeks a weekday is seen at most twice, which is
+			// not enough to separate it from the trend.
+			name: "weekly pattern too short",
+			samples: daily(10, func(d int, t time.Time) float64 {
+				if weekend(t) {
+					return 80
+				}
+				return 100
+			}),
+			anySlope: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Fit(tt.samples)
+			if tt.wantError {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.seasonal != m.HasSeasonality {
+				t.Errorf("HasSeasonality = %v, want %v", m.HasSeasonality, tt.seasonal)
+			}
+			if !tt.anySlope && math.Abs(m.Slope-tt.slope) > 1e-6 {
+				t.Errorf("Slope = %.6f, want %.6f", m.Slope, tt.slope)
+			}
+		})
+	}
+}
+
+func TestPredict(t *testing.T) {
+	line := daily(10, func(d int, _ time.Time) float64 { return 10 + 2*float64(d) })
+
+	m, err := Fit(line)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A perfect fit has no residual spread, so the interval collapses onto
+	// the extrapolated line.
+	mean, lower, upper := m.Predict(origin.Add(20 * day))
+	if math.Abs(mean-50) > 1e-6 || math.Abs(upper-lower) > 1e-6 {
+		t.Errorf("Predict(day 20) = %.4f [%.4f, %.4f], want 50 with no spread", mean, lower, upper)
+	}
+
+	noisy := daily(30, func(d int, _ time.Time) float64 { return 10 + 2*float64(d) + float64(d%3-1) })
+	m, err = Fit(noisy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	near, nearLo, nearHi := m.Predict(origin.Add(30 * day))
+	far, farLo, farHi := m.Predict(origin.Add(90 * day))
+	if !(nearLo < near && near < nearHi) {
+		t.Errorf("Predict(day 30) = %.2f [%.2f, %.2f], want a non-empty interval around the mean", near, nearLo, nearHi)
+	}
+	if farHi-farLo <= nearHi-nearLo {
+		t.Errorf("interval width %.2f at day 90, want wider than %.2f at day 30", farHi-farLo, nearHi-nearLo)
+	}
+	if math.Abs(far-190) > 1 {
+		t.Errorf("Predict(day 90) = %.2f, want about 190", far)
+	}
+}
+
+func TestPredictSeasonal(t *testing.T) {
+	samples := daily(28, func(_ int, t time.Time) float64 {
+		if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+			return 80
+		}
+		return 100
+	})
+
+	m, err := Fit(samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sat, _, _ := m.Predict(time.Date(2026, 2, 7, 12, 0, 0, 0, time.UTC))
+	mon, _, _ := m.Predict(time.Date(2026, 2, 9, 12, 0, 0, 0, time.UTC))
+	if math.Abs(sat-80) > 1 || math.Abs(mon-100) > 1 {
+		t.Errorf("Saturday %.2f, Monday %.2f, want about 80 and 100", sat, mon)
+	}
+
+	// A week-long window averages the weekly effect out.
+	week, _, _ := m.Window(origin.Add(28*day), origin.Add(35*day))
+	if want := (5*100.0 + 2*80) / 7; math.Abs(week-want) > 1 {
+		t.Errorf("Window over a week = %.2f, want about %.2f", week, want)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/events"
 	"github.com/tanay13/costguard/packages/mcp-server/pkg/fix"
 	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
 )
@@ -30,6 +31,22 @@ type PRResult struct {
 }
 
 func CreatePR(config PRConfig, actions []types.FixAction, decisionSummary types.AIDecisionSummary) (*PRResult, error) {
+	res, err := createPR(config, actions, decisionSummary)
+	if err != nil {
+		events.EmitError("github", "", err)
+		return nil, err
+	}
+
+	events.Emit(events.Event{
+		Type:    events.PRCreated,
+		Source:  "github",
+		Message: res.PRURL,
+		Payload: res,
+	})
+	return res, nil
+}
+
+func createPR(config PRConfig, actions []types.FixAction, decisionSummary types.AIDecisionSummary) (*PRResult, error) {
 
 	branchName := fmt.Sprintf("costguard/optimize-%d", time.Now().Unix())
 
@@ -37,17 +54,10 @@ func CreatePR(config PRConfig, actions []types.FixAction, decisionSummary types.
 		return nil, fmt.Errorf("failed to create branch: %w", err)
 	}
 
-	for i, action := range actions {
-
-		var decision *types.AIDecision
-		for _, d := range decisionSummary.Decisions {
-			if d.ActionID == fmt.Sprintf("action-%d", i) && d.Decision == "apply" {
-				decision = &d
-				break
-			}
-		}
+	for _, action := range actions {
 
-		if decision == nil {
+		decision := findDecision(decisionSummary, action.ID)
+		if decision == nil || decision.Decision != "apply" {
 			continue
 		}
 
@@ -197,17 +207,12 @@ func buildPRBody(actions []types.FixAction, summary types.AIDecisionSummary) str
 	body.WriteString(fmt.Sprintf("```\n%s\n```\n\n", summary.Summary))
 
 	body.WriteString("### 📝 Changes\n\n")
-	for i, action := range actions {
-		var decision *types.AIDecision
-		for _, d := range summary.Decisions {
-			if d.ActionID == fmt.Sprintf("action-%d", i) {
-				decision = &d
-				break
-			}
-		}
+	for _, action := range actions {
+		decision := findDecision(summary, action.ID)
 
 		if decision != nil && decision.Decision == "apply" {
 			body.WriteString(fmt.Sprintf("- **%s** (%s): %s\n", action.Resource, action.Intent, action.Description))
+			body.WriteString(fmt.Sprintf("  - Action ID: `%s`\n", action.ID))
 			body.WriteString(fmt.Sprintf("  - Savings: $%.2f/month\n", decision.EstimatedSavingsUSD))
 			body.WriteString(fmt.Sprintf("  - Risk: %s\n", decision.RiskLevel))
 		}
@@ -221,6 +226,20 @@ func buildPRBody(actions []types.FixAction, summary types.AIDecisionSummary) str
 	return body.String()
 }
 
+func findDecision(summary types.AIDecisionSummary, actionID string) *types.AIDecision {
+	if actionID == "" {
+		return nil
+	}
+
+	for i := range summary.Decisions {
+		if summary.Decisions[i].ActionID == actionID {
+			return &summary.Decisions[i]
+		}
+	}
+
+	return nil
+}
+
 func buildCommitMessage(actions []types.FixAction, summary types.AIDecisionSummary) string {
 	return fmt.Sprintf(
 		"perf(costguard): optimize resources (save $%.2f/month)\n\nAI-reviewed optimization with %d actions applied",
@@ -235,3 +254,17 @@ func execGit(args ...string) error {
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
+
+// CommentOnPR posts body as a comment on pull request number using the gh
+// CLI.
+func CommentOnPR(number int, body string) error {
+	cmd := exec.Command("gh", "pr", "comment", fmt.Sprintf("%d", number), "--body-file", "-")
+	cmd.Stdin = strings.NewReader(body)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to comment on PR #%d: %w (stderr: %s)", number, err, stderr.String())
+	}
+	return nil
+}
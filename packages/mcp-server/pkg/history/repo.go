@@ -0,0 +1,33 @@
+package history
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// Repo identifies the checkout a run was made from.
+type Repo struct {
+	Remote string `json:"remote,omitempty"`
+	Branch string `json:"branch,omitempty"`
+	Commit string `json:"commit,omitempty"`
+}
+
+// DetectRepo reads the identity of the git checkout in dir. Fields are left
+// empty outside a repository or when git is unavailable.
+func DetectRepo(dir string) Repo {
+	return Repo{
+		Remote: gitOutput(dir, "config", "--get", "remote.origin.url"),
+		Branch: gitOutput(dir, "rev-parse", "--abbrev-ref", "HEAD"),
+		Commit: gitOutput(dir, "rev-parse", "HEAD"),
+	}
+}
+
+func gitOutput(dir string, args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
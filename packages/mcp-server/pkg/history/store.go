@@ -0,0 +1,279 @@
+package history
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/github"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+const DefaultPath = ".costguard/history.db"
+
+type Kind string
+
+const (
+	KindScan       Kind = "scan"
+	KindFixPlan    Kind = "fix_plan"
+	KindDecisions  Kind = "decisions"
+	KindPR         Kind = "pr"
+	KindFixApplied Kind = "fix_applied"
+)
+
+var entriesBucket = []byte("entries")
+
+// Entry is one recorded run. Exactly one of the payloads is set, matching
+// Kind. Entries that follow from a scan carry its ID in ScanID.
+type Entry struct {
+	ID        string    `json:"id"`
+	Kind      Kind      `json:"kind"`
+	Timestamp time.Time `json:"timestamp"`
+	Repo      Repo      `json:"repo"`
+	ScanID    string    `json:"scan_id,omitempty"`
+
+	Scan      *types.ScanResponse      `json:"scan,omitempty"`
+	FixPlan   *types.FixPlanResponse   `json:"fix_plan,omitempty"`
+	Decisions *types.AIDecisionSummary `json:"decisions,omitempty"`
+	PR        *github.PRResult         `json:"pr,omitempty"`
+	Applied   []types.FixAction        `json:"applied,omitempty"`
+}
+
+// Query filters entries. Zero values match everything; results are newest
+// first and capped at Limit when it is positive.
+type Query struct {
+	Kind   Kind
+	Remote string
+	Since  time.Time
+	Until  time.Time
+	Limit  int
+}
+
+func (q Query) matches(e Entry) bool {
+	return (q.Kind == "" || e.Kind == q.Kind) &&
+		(q.Remote == "" || e.Repo.Remote == q.Remote) &&
+		(q.Since.IsZero() || !e.Timestamp.Before(q.Since)) &&
+		(q.Until.IsZero() || !e.Timestamp.After(q.Until))
+}
+
+// Store is an append-only log of entries in a bbolt file, keyed by a
+// monotonically increasing sequence so iteration order is insertion order.
+type Store struct {
+	db *bolt.DB
+}
+
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(entriesBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Add assigns the entry an ID (and a timestamp when it has none) and
+// persists it.
+func (s *Store) Add(e Entry) (Entry, error) {
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now().UTC()
+	}
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(entriesBucket)
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		e.ID = strconv.FormatUint(seq, 10)
+
+		raw, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		return b.Put(key(seq), raw)
+	})
+
+	return e, err
+}
+
+func (s *Store) Get(id string) (Entry, error) {
+	seq, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return Entry{}, fmt.Errorf("invalid history id %q", id)
+	}
+
+	var e Entry
+	err = s.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(entriesBucket).Get(key(seq))
+		if raw == nil {
+			return fmt.Errorf("history entry %s not found", id)
+		}
+		return json.Unmarshal(raw, &e)
+	})
+
+	return e, err
+}
+
+// Latest returns the most recent entry of kind, or ok=false when there is
+// none.
+func (s *Store) Latest(kind Kind) (Entry, bool, error) {
+	entries, err := s.List(Query{Kind: kind, Limit: 1})
+	if err != nil || len(entries) == 0 {
+		return Entry{}, false, err
+	}
+	return entries[0], true, nil
+}
+
+func (s *Store) List(q Query) ([]Entry, error) {
+	out := []Entry{}
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(entriesBucket).Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			var e Entry
+			if err := json.Unmarshal(v, &e); err != nil {
+				return err
+			}
+			if !q.matches(e) {
+				continue
+			}
+			out = append(out, e)
+			if q.Limit > 0 && len(out) >= q.Limit {
+				break
+			}
+		}
+		return nil
+	})
+
+	return out, err
+}
+
+// Applied returns the fixes recorded as applied after since and up to
+// until, oldest first.
+func (s *Store) Applied(since, until time.Time) ([]types.FixAction, error) {
+	entries, err := s.List(Query{Kind: KindFixApplied, Until: until})
+	if err != nil {
+		return nil, err
+	}
+
+	out := []types.FixAction{}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Timestamp.After(since) {
+			out = append(out, entries[i].Applied...)
+		}
+	}
+	return out, nil
+}
+
+// CostPoint is the cost of one resource or provider in one scan.
+type CostPoint struct {
+	ScanID         string         `json:"scan_id"`
+	Timestamp      time.Time      `json:"timestamp"`
+	Key            string         `json:"key"`
+	Provider       types.Provider `json:"provider,omitempty"`
+	CurrentCostUSD float64        `json:"current_cost_usd"`
+	OptimalCostUSD float64        `json:"optimal_cost_usd"`
+	SavingsUSD     float64        `json:"savings_usd"`
+}
+
+const (
+	GroupByResource = "resource"
+	GroupByProvider = "provider"
+	GroupByTotal    = "total"
+)
+
+// CostSeries returns cost over time from the scans matching q, oldest first.
+// Points are grouped by resource, provider or total; key narrows the series
+// to a single resource or provider name.
+func (s *Store) CostSeries(q Query, groupBy, key string) ([]CostPoint, error) {
+	q.Kind = KindScan
+	scans, err := s.List(q)
+	if err != nil {
+		return nil, err
+	}
+
+	out := []CostPoint{}
+	for i := len(scans) - 1; i >= 0; i-- {
+		e := scans[i]
+		if e.Scan == nil {
+			continue
+		}
+
+		if groupBy == GroupByTotal {
+			sum := e.Scan.Summary
+			out = append(out, CostPoint{
+				ScanID:         e.ID,
+				Timestamp:      e.Timestamp,
+				Key:            GroupByTotal,
+				CurrentCostUSD: sum.TotalCurrentCostUSD,
+				OptimalCostUSD: sum.TotalOptimalCostUSD,
+				SavingsUSD:     sum.TotalPotentialSavingsUSD,
+			})
+			continue
+		}
+
+		points := map[string]*CostPoint{}
+		for _, r := range e.Scan.Resources {
+			k := r.Resource
+			if groupBy == GroupByProvider {
+				k = string(r.Provider)
+			}
+			if key != "" && k != key {
+				continue
+			}
+
+			p, ok := points[k]
+			if !ok {
+				p = &CostPoint{ScanID: e.ID, Timestamp: e.Timestamp, Key: k}
+				if groupBy != GroupByProvider {
+					p.Provider = r.Provider
+				}
+				points[k] = p
+			}
+			p.CurrentCostUSD += r.Costs.CurrentCostUSD
+			p.OptimalCostUSD += r.Costs.OptimalCostUSD
+			p.SavingsUSD += r.Costs.PotentialSavingsUSD
+		}
+
+		keys := make([]string, 0, len(points))
+		for k := range points {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			out = append(out, *points[k])
+		}
+	}
+
+	return out, nil
+}
+
+func key(seq uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, seq)
+	return b
+}
@@ -0,0 +1,246 @@
+package impact
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+// costKey identifies a resource across base and head. The directory is part
+// of the key so same-named resources in different overlays or environments
+// are compared with their own counterparts.
+func costKey(m types.ManifestCost) string {
+	return manifestDir(m) + "|" + m.Kind + "/" + m.Namespace + "/" + m.Name
+}
+
+func manifestDir(m types.ManifestCost) string {
+	return fileDir(m.File)
+}
+
+func fileDir(file string) string {
+	return filepath.ToSlash(filepath.Dir(file))
+}
+
+func parse(files map[string]string) (map[string]types.ManifestCost, map[string]string) {
+	yamlFiles := map[string]string{}
+	tfFiles := map[string]string{}
+	for path, content := range files {
+		switch filepath.Ext(path) {
+		case ".yaml", ".yml":
+			yamlFiles[path] = content
+		case ".tf":
+			tfFiles[path] = content
+		}
+	}
+
+	costs, unpriced := ParseTerraform(tfFiles)
+	costs = append(costs, ParseKubernetes(yamlFiles)...)
+
+	out := map[string]types.ManifestCost{}
+	for _, c := range costs {
+		out[costKey(c)] = c
+	}
+	return out, unpriced
+}
+
+// Estimate prices the declared configuration on both sides and reports every
+// workload or instance whose monthly cost changed. A side that cannot be
+// priced is assumed to cost the same as the other, so it does not skew the
+// delta.
+func Estimate(base, head map[string]string) types.CostImpact {
+	baseCosts, baseUnpriced := parse(base)
+	headCosts, headUnpriced := parse(head)
+
+	res := types.CostImpact{Changes: []types.ManifestChange{}}
+
+	keys := []string{}
+	for k := range baseCosts {
+		keys = append(keys, k)
+	}
+	for k := range headCosts {
+		if _, ok := baseCosts[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		b, inBase := baseCosts[k]
+		h, inHead := headCosts[k]
+
+		if msg, ok := headUnpriced[k]; ok && inHead {
+			h.MonthlyCostUSD = b.MonthlyCostUSD
+			res.Unpriced = append(res.Unpriced, msg)
+		} else if msg, ok := baseUnpriced[k]; ok && inBase {
+			b.MonthlyCostUSD = h.MonthlyCostUSD
+			res.Unpriced = append(res.Unpriced, msg)
+		}
+		res.BaseCostUSD += b.MonthlyCostUSD
+		res.HeadCostUSD += h.MonthlyCostUSD
+
+		change := types.ManifestChange{}
+		switch {
+		case !inBase:
+			change = types.ManifestChange{Kind: h.Kind, Name: h.Name, Namespace: h.Namespace, Status: types.ChangeAdded, Head: &h}
+		case !inHead:
+			change = types.ManifestChange{Kind: b.Kind, Name: b.Name, Namespace: b.Namespace, Status: types.ChangeRemoved, Base: &b}
+		case b.Replicas != h.Replicas || b.Requests != h.Requests || b.InstanceType != h.InstanceType:
+			change = types.ManifestChange{Kind: h.Kind, Name: h.Name, Namespace: h.Namespace, Status: types.ChangeChanged, Base: &b, Head: &h}
+		default:
+			continue
+		}
+		if inHead {
+			change.Dir = manifestDir(h)
+		} else {
+			change.Dir = manifestDir(b)
+		}
+		change.CostDeltaUSD = h.MonthlyCostUSD - b.MonthlyCostUSD
+		res.Changes = append(res.Changes, change)
+	}
+
+	sort.SliceStable(res.Changes, func(i, j int) bool {
+		return math.Abs(res.Changes[i].CostDeltaUSD) > math.Abs(res.Changes[j].CostDeltaUSD)
+	})
+
+	res.DeltaUSD = res.HeadCostUSD - res.BaseCostUSD
+	if res.BaseCostUSD > 0 {
+		res.DeltaPercent = res.DeltaUSD / res.BaseCostUSD * 100
+	}
+	return res
+}
+
+func manifestFile(path string) bool {
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml", ".tf":
+		return true
+	}
+	return false
+}
+
+// ReadDir loads every manifest under dir, keyed by path relative to dir.
+func ReadDir(dir string) (map[string]string, error) {
+	files := map[string]string{}
+
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if name := info.Name(); name == ".git" || name == ".terraform" || name == "node_modules" || name == ".costguard" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !manifestFile(p) {
+			return nil
+		}
+
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, p)
+		files[rel] = string(content)
+		return nil
+	})
+
+	return files, err
+}
+
+// ReadGitRef loads every manifest at ref in the repository at dir, so a PR's
+// base can be priced without checking it out.
+func ReadGitRef(dir, ref string) (map[string]string, error) {
+	ls := exec.Command("git", "ls-tree", "-r", "--name-only", ref)
+	ls.Dir = dir
+	out, err := ls.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files at %s: %w", ref, err)
+	}
+
+	files := map[string]string{}
+	for _, path := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if !manifestFile(path) {
+			continue
+		}
+
+		show := exec.Command("git", "show", ref+":"+path)
+		show.Dir = dir
+		content, err := show.Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s at %s: %w", path, ref, err)
+		}
+		files[path] = string(content)
+	}
+
+	return files, nil
+}
+
+// Markdown renders the impact as a PR comment.
+func Markdown(res types.CostImpact) string {
+	var b strings.Builder
+
+	b.WriteString("## 💰 CostGuard cost impact\n\n")
+	if len(res.Changes) == 0 {
+		b.WriteString("This change does not affect declared resource costs.\n")
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf("Estimated monthly cost: **$%.2f → $%.2f** (%s, %+.1f%%)\n\n",
+		res.BaseCostUSD, res.HeadCostUSD, signedUSD(res.DeltaUSD), res.DeltaPercent))
+
+	b.WriteString("| Resource | Change | Before | After | Δ / month |\n|---|---|---|---|---:|\n")
+	for _, c := range res.Changes {
+		name := DisplayName(c)
+		b.WriteString(fmt.Sprintf("| %s `%s` | %s | %s | %s | %s |\n",
+			c.Kind, name, c.Status, describe(c.Base), describe(c.Head), signedUSD(c.CostDeltaUSD)))
+	}
+
+	if len(res.Unpriced) > 0 {
+		b.WriteString("\n**Not priced:**\n\n")
+		for _, u := range res.Unpriced {
+			b.WriteString("- " + u + "\n")
+		}
+	}
+
+	b.WriteString("\n<sub>Declared requests and instance types priced with the CostGuard catalog; actual usage is not considered.</sub>\n")
+	return b.String()
+}
+
+func describe(m *types.ManifestCost) string {
+	if m == nil {
+		return "-"
+	}
+	if m.InstanceType != "" {
+		if m.Replicas != 1 {
+			return fmt.Sprintf("%d × %s", m.Replicas, m.InstanceType)
+		}
+		return m.InstanceType
+	}
+	return fmt.Sprintf("%d × %.0fm / %.2fGi", m.Replicas, m.Requests.CpuMilli, m.Requests.MemoryGB)
+}
+
+func signedUSD(v float64) string {
+	if v < 0 {
+		return fmt.Sprintf("-$%.2f", -v)
+	}
+	return fmt.Sprintf("+$%.2f", v)
+}
+
+// DisplayName is the change's namespace/name, followed by its directory when
+// the manifests are not at the top level.
+func DisplayName(c types.ManifestChange) string {
+	name := c.Name
+	if c.Namespace != "" {
+		name = c.Namespace + "/" + c.Name
+	}
+	if c.Dir != "" && c.Dir != "." {
+		name += " (" + c.Dir + ")"
+	}
+	return name
+}
@@ -0,0 +1,155 @@
+package impact
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/provider/kubernetes"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+var documentSeparator = regexp.MustCompile(`(?m)^---.*$`)
+
+// workloadKinds are the kinds whose pods are priced. DaemonSets run one pod
+// per node, which the manifest alone cannot tell, so they count as one.
+var workloadKinds = map[string]bool{
+	"Deployment":  true,
+	"StatefulSet": true,
+	"ReplicaSet":  true,
+	"DaemonSet":   true,
+	"Pod":         true,
+}
+
+// yamlField is one "key: value" line with the path of keys leading to it.
+// List items do not add a path segment, so every container's cpu request
+// sits at "...containers.resources.requests.cpu".
+type yamlField struct {
+	path  string
+	value string
+}
+
+// parseYAMLFields flattens a single YAML document by indentation. It covers
+// the block-style manifests CostGuard prices, not the full YAML grammar.
+func parseYAMLFields(doc string) []yamlField {
+	type frame struct {
+		indent int
+		key    string
+	}
+
+	out := []yamlField{}
+	stack := []frame{}
+
+	for _, line := range strings.Split(doc, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		for strings.HasPrefix(trimmed, "- ") {
+			trimmed = strings.TrimSpace(trimmed[2:])
+			indent += 2
+		}
+
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok || strings.ContainsAny(key, " {[") {
+			continue
+		}
+		if i := strings.Index(value, " #"); i >= 0 {
+			value = value[:i]
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+
+		keys := make([]string, 0, len(stack)+1)
+		for _, f := range stack {
+			keys = append(keys, f.key)
+		}
+		keys = append(keys, key)
+
+		out = append(out, yamlField{path: strings.Join(keys, "."), value: value})
+		stack = append(stack, frame{indent: indent, key: key})
+	}
+
+	return out
+}
+
+func fieldValue(fields []yamlField, path string) string {
+	for _, f := range fields {
+		if f.path == path {
+			return f.value
+		}
+	}
+	return ""
+}
+
+// ParseKubernetes prices the workloads declared in a YAML file. Replicas
+// come from spec.replicas, or from the minReplicas of an HPA in the same
+// directory targeting the workload, since that is the floor the cluster pays
+// for. Like costKey, HPAs are matched per directory so an overlay's HPA
+// doesn't change the workloads of other overlays.
+func ParseKubernetes(files map[string]string) []types.ManifestCost {
+	out := []types.ManifestCost{}
+	hpaMin := map[string]int{}
+
+	for file, content := range files {
+		for _, doc := range documentSeparator.Split(content, -1) {
+			fields := parseYAMLFields(doc)
+			kind := fieldValue(fields, "kind")
+			name := fieldValue(fields, "metadata.name")
+			namespace := fieldValue(fields, "metadata.namespace")
+
+			if kind == "HorizontalPodAutoscaler" {
+				if n, err := strconv.Atoi(fieldValue(fields, "spec.minReplicas")); err == nil {
+					hpaMin[fileDir(file)+"|"+namespace+"/"+fieldValue(fields, "spec.scaleTargetRef.name")] = n
+				}
+				continue
+			}
+
+			if !workloadKinds[kind] || name == "" {
+				continue
+			}
+
+			m := types.ManifestCost{
+				Kind:      kind,
+				Name:      name,
+				Namespace: namespace,
+				File:      file,
+				Provider:  types.ProviderKubernetes,
+				Replicas:  1,
+			}
+			if n, err := strconv.Atoi(fieldValue(fields, "spec.replicas")); err == nil {
+				m.Replicas = n
+			}
+
+			for _, f := range fields {
+				switch {
+				case strings.HasSuffix("."+f.path, ".containers.resources.requests.cpu"):
+					if v, err := kubernetes.ParseCPUMilli(f.value); err == nil {
+						m.Requests.CpuMilli += v
+					}
+				case strings.HasSuffix("."+f.path, ".containers.resources.requests.memory"):
+					if v, err := kubernetes.ParseMemoryGB(f.value); err == nil {
+						m.Requests.MemoryGB += v
+					}
+				}
+			}
+
+			out = append(out, m)
+		}
+	}
+
+	for i := range out {
+		if n, ok := hpaMin[manifestDir(out[i])+"|"+out[i].Namespace+"/"+out[i].Name]; ok {
+			out[i].Replicas = n
+		}
+		out[i].MonthlyCostUSD = kubernetes.ComputeCostFromRequests(out[i].Requests.CpuMilli, out[i].Requests.MemoryGB) *
+			float64(out[i].Replicas)
+	}
+
+	return out
+}
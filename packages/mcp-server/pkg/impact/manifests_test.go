@@ -0,0 +1,207 @@
+package impact
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/provider/kubernetes"
+)
+
+func TestParseYAMLFields(t *testing.T) {
+	doc := `apiVersion: apps/v1
+kind: Deployment # the workload
+metadata:
+  name: "web"
+  labels:
+    team: 'payments'
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+        - name: app
+          image: nginx:1.27
+          resources:
+            requests:
+              cpu: 250m
+              memory: 512Mi
+        - name: sidecar
+          resources:
+            requests:
+              cpu: 50m
+      args: [--verbose]
+`
+	fields := parseYAMLFields(doc)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"kind", "Deployment"},
+		{"metadata.name", "web"},
+		{"metadata.labels.team", "payments"},
+		{"spec.replicas", "3"},
+		{"spec.template.spec.containers.name", "app"},
+		{"spec.template.spec.containers.image", "nginx:1.27"},
+		{"spec.template.spec.containers.resources.requests.cpu", "250m"},
+		{"spec.template.spec.containers.resources.requests.memory", "512Mi"},
+		{"spec.template.spec.args", "[--verbose]"},
+		{"spec.missing", ""},
+	}
+	for _, tt := range tests {
+		if got := fieldValue(fields, tt.path); got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+
+	cpus := 0
+	for _, f := range fields {
+		if f.path == "spec.template.spec.containers.resources.requests.cpu" {
+			cpus++
+		}
+	}
+	if cpus != 2 {
+		t.Errorf("found %d container cpu requests, want 2", cpus)
+	}
+}
+
+const deployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: shop
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+        - name: app
+          resources:
+            requests:
+              cpu: 500m
+              memory: 1Gi
+`
+
+func hpa(min string) string {
+	return `apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: web
+  namespace: shop
+spec:
+  scaleTargetRef:
+    kind: Deployment
+    name: web
+  minReplicas: ` + min + `
+  maxReplicas: 20
+`
+}
+
+func TestParseKubernetesReplicas(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  map[string]int // file → replicas
+	}{
+		{
+			name:  "spec.replicas",
+			files: map[string]string{"base/deploy.yaml": deployment},
+			want:  map[string]int{"base/deploy.yaml": 2},
+		},
+		{
+			name:  "hpa in the same file",
+			files: map[string]string{"base/deploy.yaml": deployment + "---\n" + hpa("4")},
+			want:  map[string]int{"base/deploy.yaml": 4},
+		},
+		{
+			name: "hpa in the same directory",
+			files: map[string]string{
+				"base/deploy.yaml": deployment,
+				"base/hpa.yaml":    hpa("5"),
+			},
+			want: map[string]int{"base/deploy.yaml": 5},
+		},
+		{
+			name: "hpa in another overlay",
+			files: map[string]string{
+				"overlays/dev/deploy.yaml": deployment,
+				"overlays/prod/hpa.yaml":   hpa("6"),
+			},
+			want: map[string]int{"overlays/dev/deploy.yaml": 2},
+		},
+		{
+			name: "one hpa per overlay",
+			files: map[string]string{
+				"overlays/dev/deploy.yaml":  deployment,
+				"overlays/dev/hpa.yaml":     hpa("1"),
+				"overlays/prod/deploy.yaml": deployment,
+				"overlays/prod/hpa.yaml":    hpa("8"),
+			},
+			want: map[string]int{"overlays/dev/deploy.yaml": 1, "overlays/prod/deploy.yaml": 8},
+		},
+	}
+
+	perPod := kubernetes.ComputeCostFromRequests(500, 1)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Files are visited in map order, so repeat to catch results that
+			// depend on which HPA happens to be seen last.
+			for run := 0; run < 20; run++ {
+				got := ParseKubernetes(tt.files)
+				if len(got) != len(tt.want) {
+					t.Fatalf("got %d workloads, want %d", len(got), len(tt.want))
+				}
+				for _, m := range got {
+					want, ok := tt.want[m.File]
+					if !ok {
+						t.Fatalf("unexpected workload in %s", m.File)
+					}
+					if m.Replicas != want {
+						t.Fatalf("%s: Replicas = %d, want %d", m.File, m.Replicas, want)
+					}
+					if math.Abs(m.MonthlyCostUSD-perPod*float64(want)) > 1e-9 {
+						t.Fatalf("%s: MonthlyCostUSD = %.4f, want %.4f", m.File, m.MonthlyCostUSD, perPod*float64(want))
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestParseKubernetesRequests(t *testing.T) {
+	files := map[string]string{"app.yaml": `kind: StatefulSet
+metadata:
+  name: db
+spec:
+  template:
+    spec:
+      containers:
+        - name: db
+          resources:
+            requests:
+              cpu: "1"
+              memory: 2Gi
+        - name: exporter
+          resources:
+            requests:
+              cpu: 100m
+              memory: 128Mi
+---
+kind: ConfigMap
+metadata:
+  name: settings
+`}
+
+	got := ParseKubernetes(files)
+	if len(got) != 1 {
+		t.Fatalf("got %d workloads, want 1", len(got))
+	}
+	m := got[0]
+	if m.Kind != "StatefulSet" || m.Name != "db" || m.Replicas != 1 {
+		t.Errorf("got %s/%s ×%d, want StatefulSet/db ×1", m.Kind, m.Name, m.Replicas)
+	}
+	if m.Requests.CpuMilli != 1100 || math.Abs(m.Requests.MemoryGB-(2+0.125)) > 1e-9 {
+		t.Errorf("Requests = %+v, want 1100m and 2.125GB summed over containers", m.Requests)
+	}
+}
@@ -0,0 +1,115 @@
+package impact
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/pricing"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+var terraformBlockPattern = regexp.MustCompile(`(?m)^\s*resource\s+"([^"]+)"\s+"([^"]+)"\s*\{`)
+
+// terraformInstance says which attribute of a resource type holds its size
+// and which catalog prices it.
+type terraformInstance struct {
+	provider types.Provider
+	attr     string
+	catalog  func() map[string]pricing.MachineType
+}
+
+var terraformInstances = map[string]terraformInstance{
+	"google_compute_instance":          {types.ProviderGCPGCE, "machine_type", gceCatalog},
+	"google_compute_instance_template": {types.ProviderGCPGCE, "machine_type", gceCatalog},
+	"azurerm_linux_virtual_machine":    {types.ProviderAzureVM, "size", azureCatalog},
+	"azurerm_windows_virtual_machine":  {types.ProviderAzureVM, "size", azureCatalog},
+	"azurerm_virtual_machine":          {types.ProviderAzureVM, "vm_size", azureCatalog},
+	"aws_db_instance":                  {types.ProviderAWSRDS, "instance_class", rdsCatalog},
+}
+
+func gceCatalog() map[string]pricing.MachineType   { return pricing.Default.GCP.MachineTypes }
+func azureCatalog() map[string]pricing.MachineType { return pricing.Default.Azure.VMSizes }
+func rdsCatalog() map[string]pricing.MachineType   { return pricing.Default.AWS.RDS.InstanceClasses }
+
+// ParseTerraform prices instances declared in Terraform files by their
+// machine type. count multiplies the cost and Multi-AZ databases pay for a
+// standby. Sizes set through variables or missing from the catalog are
+// priced at zero and explained in unpriced, keyed like the costs.
+func ParseTerraform(files map[string]string) ([]types.ManifestCost, map[string]string) {
+	out := []types.ManifestCost{}
+	unpriced := map[string]string{}
+
+	for file, content := range files {
+		for _, loc := range terraformBlockPattern.FindAllStringSubmatchIndex(content, -1) {
+			blockType := content[loc[2]:loc[3]]
+			label := content[loc[4]:loc[5]]
+
+			inst, ok := terraformInstances[blockType]
+			if !ok {
+				continue
+			}
+
+			block := content[loc[1]:blockEnd(content, loc[1])]
+			size := terraformAttribute(block, inst.attr)
+
+			m := types.ManifestCost{
+				Kind:         blockType,
+				Name:         label,
+				File:         file,
+				Provider:     inst.provider,
+				Replicas:     1,
+				InstanceType: size,
+			}
+			if n, err := strconv.Atoi(terraformAttribute(block, "count")); err == nil {
+				m.Replicas = n
+			}
+
+			machine, known := inst.catalog()[size]
+			if !known {
+				unpriced[costKey(m)] = fmt.Sprintf("%s.%s: %s %q is not in the pricing catalog", blockType, label, inst.attr, size)
+			}
+
+			m.MonthlyCostUSD = machine.HourlyUSD * pricing.HoursPerMonth * float64(m.Replicas)
+			if terraformAttribute(block, "multi_az") == "true" {
+				m.MonthlyCostUSD *= 2
+			}
+
+			out = append(out, m)
+		}
+	}
+
+	return out, unpriced
+}
+
+// terraformAttribute returns a top-level attribute of a block, unquoted.
+// Expressions such as var.size are returned as written.
+func terraformAttribute(block, attr string) string {
+	pattern := regexp.MustCompile(`(?m)^\s*` + regexp.QuoteMeta(attr) + `\s*=\s*(?:"([^"]*)"|([^\s#]+))`)
+	m := pattern.FindStringSubmatch(block)
+	if m == nil {
+		return ""
+	}
+	if m[1] != "" {
+		return m[1]
+	}
+	return m[2]
+}
+
+// blockEnd returns the index of the closing brace of the block whose opening
+// brace ends right before start.
+func blockEnd(content string, start int) int {
+	depth := 1
+	for i := start; i < len(content); i++ {
+		switch content[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(content)
+}
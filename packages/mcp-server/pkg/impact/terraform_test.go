@@ -0,0 +1,130 @@
+package impact
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/pricing"
+)
+
+func TestParseTerraform(t *testing.T) {
+	hourly := func(catalog map[string]pricing.MachineType, size string) float64 {
+		return catalog[size].HourlyUSD * pricing.HoursPerMonth
+	}
+
+	tests := []struct {
+		name     string
+		content  string
+		replicas int
+		cost     float64
+		unpriced bool
+	}{
+		{
+			name: "gce instance",
+			content: `resource "google_compute_instance" "web" {
+  name         = "web"
+  machine_type = "e2-standard-4"
+}`,
+			replicas: 1,
+			cost:     hourly(gceCatalog(), "e2-standard-4"),
+		},
+		{
+			name: "count multiplies",
+			content: `resource "google_compute_instance" "web" {
+  count        = 3
+  machine_type = "e2-medium" # burstable
+  boot_disk {
+    initialize_params {
+      image = "debian-12"
+    }
+  }
+}`,
+			replicas: 3,
+			cost:     3 * hourly(gceCatalog(), "e2-medium"),
+		},
+		{
+			name: "multi-az database",
+			content: `resource "aws_db_instance" "orders" {
+  instance_class = "db.t3.medium"
+  multi_az       = true
+}`,
+			replicas: 1,
+			cost:     2 * hourly(rdsCatalog(), "db.t3.medium"),
+		},
+		{
+			name: "size from a variable",
+			content: `resource "azurerm_linux_virtual_machine" "vm" {
+  size = var.vm_size
+}`,
+			replicas: 1,
+			unpriced: true,
+		},
+		{
+			name: "unknown machine type",
+			content: `resource "google_compute_instance" "web" {
+  machine_type = "z9-imaginary"
+}`,
+			replicas: 1,
+			unpriced: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, unpriced := ParseTerraform(map[string]string{"infra/main.tf": tt.content})
+			if len(got) != 1 {
+				t.Fatalf("got %d resources, want 1", len(got))
+			}
+			m := got[0]
+			if m.Replicas != tt.replicas {
+				t.Errorf("Replicas = %d, want %d", m.Replicas, tt.replicas)
+			}
+			if math.Abs(m.MonthlyCostUSD-tt.cost) > 1e-9 {
+				t.Errorf("MonthlyCostUSD = %.4f, want %.4f", m.MonthlyCostUSD, tt.cost)
+			}
+			if _, ok := unpriced[costKey(m)]; ok != tt.unpriced {
+				t.Errorf("unpriced = %v, want %v", unpriced, tt.unpriced)
+			}
+		})
+	}
+}
+
+func TestParseTerraformIgnoresOtherResources(t *testing.T) {
+	content := `resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+
+resource "google_compute_instance" "web" {
+  machine_type = "e2-small"
+}
+`
+	got, _ := ParseTerraform(map[string]string{"main.tf": content})
+	if len(got) != 1 || got[0].Name != "web" {
+		t.Fatalf("got %+v, want only google_compute_instance.web", got)
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	base := map[string]string{
+		"overlays/dev/deploy.yaml":  deployment,
+		"overlays/prod/deploy.yaml": deployment,
+		"overlays/prod/hpa.yaml":    hpa("4"),
+	}
+	head := map[string]string{
+		"overlays/dev/deploy.yaml":  deployment,
+		"overlays/prod/deploy.yaml": deployment,
+		"overlays/prod/hpa.yaml":    hpa("6"),
+	}
+
+	res := Estimate(base, head)
+	if len(res.Changes) != 1 {
+		t.Fatalf("got %d changes, want only the prod overlay: %+v", len(res.Changes), res.Changes)
+	}
+	c := res.Changes[0]
+	if c.Dir != "overlays/prod" || c.Base.Replicas != 4 || c.Head.Replicas != 6 {
+		t.Errorf("change = %s %d → %d, want overlays/prod 4 → 6", c.Dir, c.Base.Replicas, c.Head.Replicas)
+	}
+	if math.Abs(res.DeltaUSD-c.CostDeltaUSD) > 1e-9 || res.DeltaUSD <= 0 {
+		t.Errorf("DeltaUSD = %.4f, change delta %.4f, want equal and positive", res.DeltaUSD, c.CostDeltaUSD)
+	}
+}
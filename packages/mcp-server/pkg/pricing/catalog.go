@@ -0,0 +1,274 @@
+package pricing
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+const (
+	HoursPerMonth   = 24 * 30
+	SecondsPerMonth = HoursPerMonth * 60 * 60
+)
+
+// Catalog holds the list prices every provider's cost model is built on.
+// Prices are on-demand unless stated otherwise.
+type Catalog struct {
+	Kubernetes KubernetesPricing       `json:"kubernetes"`
+	Storage    map[string]StorageClass `json:"storage_classes"`
+	GCP        GCPPricing              `json:"gcp"`
+	Azure      AzurePricing            `json:"azure"`
+	AWS        AWSPricing              `json:"aws"`
+	Network    NetworkPricing          `json:"network"`
+}
+
+// NetworkPricing is per GB transferred. Cross-zone traffic is billed on both
+// sides, so CrossZonePerGB is the sum of the two.
+type NetworkPricing struct {
+	InternetEgressPerGB float64 `json:"internet_egress_per_gb"`
+	CrossZonePerGB      float64 `json:"cross_zone_per_gb"`
+	CrossRegionPerGB    float64 `json:"cross_region_per_gb"`
+}
+
+type KubernetesPricing struct {
+	CPUPerMilliHour float64 `json:"cpu_per_milli_hour"`
+	MemoryPerGBHour float64 `json:"memory_per_gb_hour"`
+
+	// SpotDiscount is the fraction knocked off on-demand compute when a
+	// workload runs on spot/preemptible capacity.
+	SpotDiscount     float64 `json:"spot_discount"`
+	SpotNodeSelector Label   `json:"spot_node_selector"`
+	SpotToleration   Label   `json:"spot_toleration"`
+
+	// NodePools maps a node pool name to its price relative to the default
+	// on-demand pool (1.0). NodePoolLabel is the label selecting a pool.
+	NodePools     map[string]float64 `json:"node_pools,omitempty"`
+	NodePoolLabel string             `json:"node_pool_label"`
+}
+
+type StorageClass struct {
+	PerGBMonth float64 `json:"per_gb_month"`
+	// Downgrade names a cheaper class that can serve the same workloads,
+	// e.g. gp2 → gp3. Empty when there is none.
+	Downgrade string `json:"downgrade,omitempty"`
+}
+
+type GCPPricing struct {
+	CloudRun     CloudRunPricing        `json:"cloud_run"`
+	MachineTypes map[string]MachineType `json:"machine_types"`
+}
+
+type CloudRunPricing struct {
+	VCPUSecond         float64 `json:"vcpu_second"`
+	GiBSecond          float64 `json:"gib_second"`
+	PerMillionRequests float64 `json:"per_million_requests"`
+	// Idle prices apply to min-instances that are kept warm but not serving.
+	IdleVCPUSecond float64 `json:"idle_vcpu_second"`
+	IdleGiBSecond  float64 `json:"idle_gib_second"`
+}
+
+type AzurePricing struct {
+	ContainerApps ContainerAppsPricing   `json:"container_apps"`
+	VMSizes       map[string]MachineType `json:"vm_sizes"`
+}
+
+type ContainerAppsPricing struct {
+	VCPUSecond     float64 `json:"vcpu_second"`
+	GiBSecond      float64 `json:"gib_second"`
+	IdleVCPUSecond float64 `json:"idle_vcpu_second"`
+	IdleGiBSecond  float64 `json:"idle_gib_second"`
+}
+
+type AWSPricing struct {
+	Fargate FargatePricing `json:"fargate"`
+	RDS     RDSPricing     `json:"rds"`
+	S3      S3Pricing      `json:"s3"`
+}
+
+// S3Pricing also prices the object_storage provider, so S3-compatible
+// stores only need a catalog overlay.
+type S3Pricing struct {
+	Classes        map[string]ObjectStorageClass `json:"classes"`
+	GetPerThousand float64                       `json:"get_per_thousand"`
+	PutPerThousand float64                       `json:"put_per_thousand"`
+}
+
+type ObjectStorageClass struct {
+	PerGBMonth     float64 `json:"per_gb_month"`
+	RetrievalPerGB float64 `json:"retrieval_per_gb"`
+	MinDays        int     `json:"min_days"`
+}
+
+type RDSPricing struct {
+	InstanceClasses map[string]MachineType `json:"instance_classes"`
+	Storage         map[string]RDSStorage  `json:"storage"`
+}
+
+// RDSStorage prices one storage type. BaselineIOPS are included in the
+// per-GB price; provisioned IOPS above it are billed per IOPS-month.
+type RDSStorage struct {
+	PerGBMonth   float64 `json:"per_gb_month"`
+	PerIOPSMonth float64 `json:"per_iops_month"`
+	BaselineIOPS float64 `json:"baseline_iops"`
+}
+
+type FargatePricing struct {
+	VCPUHour float64 `json:"vcpu_hour"`
+	GBHour   float64 `json:"gb_hour"`
+}
+
+type MachineType struct {
+	VCPU      float64 `json:"vcpu"`
+	MemoryGB  float64 `json:"memory_gb"`
+	HourlyUSD float64 `json:"hourly_usd"`
+}
+
+type Label struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+var Default = Catalog{
+	Kubernetes: KubernetesPricing{
+		CPUPerMilliHour:  0.00001,
+		MemoryPerGBHour:  0.00002,
+		SpotDiscount:     0.65,
+		SpotNodeSelector: Label{Key: "karpenter.sh/capacity-type", Value: "spot"},
+		SpotToleration:   Label{Key: "costguard.io/spot", Value: "true"},
+		NodePools: map[string]float64{
+			"default": 1.0,
+		},
+		NodePoolLabel: "costguard.io/node-pool",
+	},
+	Storage: map[string]StorageClass{
+		"gp2":                 {PerGBMonth: 0.10, Downgrade: "gp3"},
+		"gp3":                 {PerGBMonth: 0.08},
+		"io1":                 {PerGBMonth: 0.125, Downgrade: "gp3"},
+		"io2":                 {PerGBMonth: 0.125, Downgrade: "gp3"},
+		"st1":                 {PerGBMonth: 0.045},
+		"sc1":                 {PerGBMonth: 0.015},
+		"standard":            {PerGBMonth: 0.04},
+		"standard-rwo":        {PerGBMonth: 0.10},
+		"premium-rwo":         {PerGBMonth: 0.17, Downgrade: "standard-rwo"},
+		"managed-csi":         {PerGBMonth: 0.075},
+		"managed-csi-premium": {PerGBMonth: 0.15, Downgrade: "managed-csi"},
+	},
+	GCP: GCPPricing{
+		CloudRun: CloudRunPricing{
+			VCPUSecond:         0.000024,
+			GiBSecond:          0.0000025,
+			PerMillionRequests: 0.40,
+			IdleVCPUSecond:     0.0000025,
+			IdleGiBSecond:      0.0000025,
+		},
+		MachineTypes: map[string]MachineType{
+			"e2-micro":       {VCPU: 2, MemoryGB: 1, HourlyUSD: 0.0084},
+			"e2-small":       {VCPU: 2, MemoryGB: 2, HourlyUSD: 0.0168},
+			"e2-medium":      {VCPU: 2, MemoryGB: 4, HourlyUSD: 0.0335},
+			"e2-standard-2":  {VCPU: 2, MemoryGB: 8, HourlyUSD: 0.0670},
+			"e2-standard-4":  {VCPU: 4, MemoryGB: 16, HourlyUSD: 0.1340},
+			"e2-standard-8":  {VCPU: 8, MemoryGB: 32, HourlyUSD: 0.2681},
+			"e2-standard-16": {VCPU: 16, MemoryGB: 64, HourlyUSD: 0.5362},
+			"e2-highmem-2":   {VCPU: 2, MemoryGB: 16, HourlyUSD: 0.0904},
+			"e2-highmem-4":   {VCPU: 4, MemoryGB: 32, HourlyUSD: 0.1809},
+			"e2-highcpu-4":   {VCPU: 4, MemoryGB: 4, HourlyUSD: 0.0989},
+			"e2-highcpu-8":   {VCPU: 8, MemoryGB: 8, HourlyUSD: 0.1978},
+			"n2-standard-2":  {VCPU: 2, MemoryGB: 8, HourlyUSD: 0.0971},
+			"n2-standard-4":  {VCPU: 4, MemoryGB: 16, HourlyUSD: 0.1942},
+			"n2-standard-8":  {VCPU: 8, MemoryGB: 32, HourlyUSD: 0.3885},
+			"n2-standard-16": {VCPU: 16, MemoryGB: 64, HourlyUSD: 0.7769},
+			"n2-highmem-2":   {VCPU: 2, MemoryGB: 16, HourlyUSD: 0.1310},
+			"n2-highmem-4":   {VCPU: 4, MemoryGB: 32, HourlyUSD: 0.2620},
+		},
+	},
+	Azure: AzurePricing{
+		ContainerApps: ContainerAppsPricing{
+			VCPUSecond:     0.000024,
+			GiBSecond:      0.000003,
+			IdleVCPUSecond: 0.000003,
+			IdleGiBSecond:  0.000003,
+		},
+		VMSizes: map[string]MachineType{
+			"Standard_B2s":     {VCPU: 2, MemoryGB: 4, HourlyUSD: 0.0416},
+			"Standard_B2ms":    {VCPU: 2, MemoryGB: 8, HourlyUSD: 0.0832},
+			"Standard_B4ms":    {VCPU: 4, MemoryGB: 16, HourlyUSD: 0.1660},
+			"Standard_D2s_v5":  {VCPU: 2, MemoryGB: 8, HourlyUSD: 0.0960},
+			"Standard_D4s_v5":  {VCPU: 4, MemoryGB: 16, HourlyUSD: 0.1920},
+			"Standard_D8s_v5":  {VCPU: 8, MemoryGB: 32, HourlyUSD: 0.3840},
+			"Standard_D16s_v5": {VCPU: 16, MemoryGB: 64, HourlyUSD: 0.7680},
+			"Standard_E2s_v5":  {VCPU: 2, MemoryGB: 16, HourlyUSD: 0.1260},
+			"Standard_E4s_v5":  {VCPU: 4, MemoryGB: 32, HourlyUSD: 0.2520},
+			"Standard_E8s_v5":  {VCPU: 8, MemoryGB: 64, HourlyUSD: 0.5040},
+			"Standard_F2s_v2":  {VCPU: 2, MemoryGB: 4, HourlyUSD: 0.0846},
+			"Standard_F4s_v2":  {VCPU: 4, MemoryGB: 8, HourlyUSD: 0.1690},
+			"Standard_F8s_v2":  {VCPU: 8, MemoryGB: 16, HourlyUSD: 0.3380},
+		},
+	},
+	AWS: AWSPricing{
+		Fargate: FargatePricing{
+			VCPUHour: 0.04048,
+			GBHour:   0.004445,
+		},
+		RDS: RDSPricing{
+			InstanceClasses: map[string]MachineType{
+				"db.t3.medium":   {VCPU: 2, MemoryGB: 4, HourlyUSD: 0.068},
+				"db.t3.large":    {VCPU: 2, MemoryGB: 8, HourlyUSD: 0.136},
+				"db.m5.large":    {VCPU: 2, MemoryGB: 8, HourlyUSD: 0.171},
+				"db.m5.xlarge":   {VCPU: 4, MemoryGB: 16, HourlyUSD: 0.342},
+				"db.m5.2xlarge":  {VCPU: 8, MemoryGB: 32, HourlyUSD: 0.684},
+				"db.m5.4xlarge":  {VCPU: 16, MemoryGB: 64, HourlyUSD: 1.368},
+				"db.m6g.large":   {VCPU: 2, MemoryGB: 8, HourlyUSD: 0.152},
+				"db.m6g.xlarge":  {VCPU: 4, MemoryGB: 16, HourlyUSD: 0.304},
+				"db.m6g.2xlarge": {VCPU: 8, MemoryGB: 32, HourlyUSD: 0.608},
+				"db.r5.large":    {VCPU: 2, MemoryGB: 16, HourlyUSD: 0.240},
+				"db.r5.xlarge":   {VCPU: 4, MemoryGB: 32, HourlyUSD: 0.480},
+				"db.r5.2xlarge":  {VCPU: 8, MemoryGB: 64, HourlyUSD: 0.960},
+				"db.r6g.large":   {VCPU: 2, MemoryGB: 16, HourlyUSD: 0.215},
+				"db.r6g.xlarge":  {VCPU: 4, MemoryGB: 32, HourlyUSD: 0.430},
+				"db.r6g.2xlarge": {VCPU: 8, MemoryGB: 64, HourlyUSD: 0.860},
+			},
+			Storage: map[string]RDSStorage{
+				"gp2": {PerGBMonth: 0.115},
+				"gp3": {PerGBMonth: 0.092, PerIOPSMonth: 0.02, BaselineIOPS: 3000},
+				"io1": {PerGBMonth: 0.125, PerIOPSMonth: 0.10},
+			},
+		},
+		S3: S3Pricing{
+			Classes: map[string]ObjectStorageClass{
+				"STANDARD":     {PerGBMonth: 0.023},
+				"STANDARD_IA":  {PerGBMonth: 0.0125, RetrievalPerGB: 0.01, MinDays: 30},
+				"GLACIER_IR":   {PerGBMonth: 0.004, RetrievalPerGB: 0.03, MinDays: 90},
+				"GLACIER":      {PerGBMonth: 0.0036, RetrievalPerGB: 0.01, MinDays: 90},
+				"DEEP_ARCHIVE": {PerGBMonth: 0.00099, RetrievalPerGB: 0.02, MinDays: 180},
+			},
+			GetPerThousand: 0.0004,
+			PutPerThousand: 0.005,
+		},
+	},
+	Network: NetworkPricing{
+		InternetEgressPerGB: 0.09,
+		CrossZonePerGB:      0.02,
+		CrossRegionPerGB:    0.02,
+	},
+}
+
+// Load overlays the JSON catalog at path onto Default, so a file only needs
+// the prices it wants to change.
+func Load(path string) (Catalog, error) {
+	// Round-trip Default through JSON so the overlay below never writes into
+	// Default's maps.
+	var cat Catalog
+	base, _ := json.Marshal(Default)
+	json.Unmarshal(base, &cat)
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return cat, fmt.Errorf("failed to read pricing catalog: %w", err)
+	}
+
+	if err := json.Unmarshal(raw, &cat); err != nil {
+		return cat, fmt.Errorf("invalid pricing catalog: %w", err)
+	}
+
+	return cat, nil
+}
@@ -0,0 +1,60 @@
+package pricing
+
+import (
+	"sort"
+	"strings"
+)
+
+// Series is the family prefix of a machine type name: "e2" for
+// "e2-standard-4", "m5" for "m5.large", "db.r5" for "db.r5.large",
+// "Standard_D" for "Standard_D4s_v5".
+func Series(name string) string {
+	if rest, ok := strings.CutPrefix(name, "db."); ok {
+		return "db." + Series(rest)
+	}
+	if i := strings.IndexAny(name, "-."); i > 0 {
+		return name[:i]
+	}
+	if strings.HasPrefix(name, "Standard_") {
+		rest := strings.TrimPrefix(name, "Standard_")
+		end := strings.IndexFunc(rest, func(r rune) bool { return r >= '0' && r <= '9' })
+		if end > 0 {
+			return "Standard_" + rest[:end]
+		}
+	}
+	return name
+}
+
+// CheapestFit returns the cheapest machine type with at least vcpu and
+// memGB, preferring the given series and falling back to any series. The
+// name is empty when nothing in the catalog is big enough.
+func CheapestFit(machines map[string]MachineType, vcpu, memGB float64, series string) (string, MachineType) {
+	names := make([]string, 0, len(machines))
+	for name := range machines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pick := func(sameSeries bool) (string, MachineType) {
+		best := ""
+		var bestType MachineType
+		for _, name := range names {
+			m := machines[name]
+			if sameSeries && Series(name) != series {
+				continue
+			}
+			if m.VCPU < vcpu || m.MemoryGB < memGB {
+				continue
+			}
+			if best == "" || m.HourlyUSD < bestType.HourlyUSD {
+				best, bestType = name, m
+			}
+		}
+		return best, bestType
+	}
+
+	if name, m := pick(true); name != "" {
+		return name, m
+	}
+	return pick(false)
+}
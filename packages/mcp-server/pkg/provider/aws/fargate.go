@@ -0,0 +1,200 @@
+package aws
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/pricing"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/provider/kubernetes"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/utils"
+)
+
+// FargateSize is a task size in ECS units: CPU units (1024 per vCPU) and MiB.
+type FargateSize struct {
+	CPU       int
+	MemoryMiB int
+}
+
+func (s FargateSize) VCPU() float64 {
+	return float64(s.CPU) / 1024
+}
+
+func (s FargateSize) MemoryGB() float64 {
+	return float64(s.MemoryMiB) / 1024
+}
+
+func (s FargateSize) String() string {
+	return fmt.Sprintf("%d/%d", s.CPU, s.MemoryMiB)
+}
+
+// HourlyCost is the on-demand price of one task of this size.
+func (s FargateSize) HourlyCost() float64 {
+	p := pricing.Default.AWS.Fargate
+	return s.VCPU()*p.VCPUHour + s.MemoryGB()*p.GBHour
+}
+
+// FargateSizes lists every CPU/memory combination Fargate accepts.
+var FargateSizes = fargateSizes()
+
+func fargateSizes() []FargateSize {
+	ranges := []struct {
+		cpu, minMiB, maxMiB, stepMiB int
+	}{
+		{512, 1024, 4096, 1024},
+		{1024, 2048, 8192, 1024},
+		{2048, 4096, 16384, 1024},
+		{4096, 8192, 30720, 1024},
+		{8192, 16384, 61440, 4096},
+		{16384, 32768, 122880, 8192},
+	}
+
+	out := []FargateSize{{256, 512}, {256, 1024}, {256, 2048}}
+	for _, r := range ranges {
+		for mem := r.minMiB; mem <= r.maxMiB; mem += r.stepMiB {
+			out = append(out, FargateSize{r.cpu, mem})
+		}
+	}
+	return out
+}
+
+// CheapestFargateSize returns the cheapest valid size with at least vcpu and
+// memGB. ok is false when nothing is big enough.
+func CheapestFargateSize(vcpu, memGB float64) (FargateSize, bool) {
+	var best FargateSize
+	found := false
+	for _, s := range FargateSizes {
+		if s.VCPU() < vcpu || s.MemoryGB() < memGB {
+			continue
+		}
+		if !found || s.HourlyCost() < best.HourlyCost() {
+			best, found = s, true
+		}
+	}
+	return best, found
+}
+
+// AggregateFargate converts task utilisation into absolute CPU and memory so
+// the scan's recommender picks the percentile and headroom, then snaps the
+// result to the cheapest valid Fargate size. Tasks are never grown.
+func AggregateFargate(resources map[string][]types.MetricCollection, rec kubernetes.Recommender) []types.AggregatedMetrics {
+	out := []types.AggregatedMetrics{}
+
+	for name, pts := range resources {
+		sort.Slice(pts, func(i, j int) bool {
+			return pts[i].TimeStamp < pts[j].TimeStamp
+		})
+
+		latest := pts[len(pts)-1].Metrics.ECSTaskResourceMetrics
+		current := FargateSize{CPU: int(latest.TaskCpu), MemoryMiB: int(latest.TaskMemoryMiB)}
+
+		cpuPct := make([]float64, 0, len(pts))
+		memPct := make([]float64, 0, len(pts))
+		cpuMilli := make([]float64, 0, len(pts))
+		memGB := make([]float64, 0, len(pts))
+		tasks := make([]float64, 0, len(pts))
+		samples := make([]types.MetricCollection, 0, len(pts))
+
+		for _, p := range pts {
+			m := p.Metrics.ECSTaskResourceMetrics
+			cpu := m.CpuUtilization / 100 * current.VCPU() * 1000
+			mem := m.MemoryUtilization / 100 * current.MemoryGB()
+
+			cpuPct = append(cpuPct, m.CpuUtilization)
+			memPct = append(memPct, m.MemoryUtilization)
+			cpuMilli = append(cpuMilli, cpu)
+			memGB = append(memGB, mem)
+			tasks = append(tasks, m.RunningTasks)
+			samples = append(samples, types.MetricCollection{
+				Provider:  p.Provider,
+				Resource:  p.Resource,
+				TimeStamp: p.TimeStamp,
+				Metrics: types.ResourceMetrics{
+					K8sResourceMetrics: types.K8sResourceMetrics{CpuMilli: cpu, MemoryGB: mem},
+				},
+			})
+		}
+
+		metrics := map[string]types.MetricStat{
+			"cpu_percent":    utils.ComputeStat(cpuPct),
+			"memory_percent": utils.ComputeStat(memPct),
+			"cpu_milli":      utils.ComputeStat(cpuMilli),
+			"memory_gb":      utils.ComputeStat(memGB),
+			"running_tasks":  utils.ComputeStat(tasks),
+		}
+
+		recommended := rec.Recommend(kubernetes.UsageProfile{
+			CPU:     metrics["cpu_milli"],
+			Memory:  metrics["memory_gb"],
+			Samples: samples,
+		})
+
+		target := current
+		if size, ok := CheapestFargateSize(recommended.CpuMilli/1000, recommended.MemoryGB); ok && size.HourlyCost() < current.HourlyCost() {
+			target = size
+		}
+
+		taskHours := metrics["running_tasks"].Avg * pricing.HoursPerMonth
+		currentCost := taskHours * current.HourlyCost()
+		optimalCost := taskHours * target.HourlyCost()
+
+		out = append(out, types.AggregatedMetrics{
+			Provider:    types.ProviderAWSECSFargate,
+			Resource:    name,
+			Metrics:     metrics,
+			Recommender: rec.Name(),
+			Machine: &types.MachineInfo{
+				InstanceType:        current.String(),
+				VCPU:                current.VCPU(),
+				MemoryGB:            current.MemoryGB(),
+				RecommendedType:     target.String(),
+				RecommendedVCPU:     target.VCPU(),
+				RecommendedMemoryGB: target.MemoryGB(),
+
+				HourlyUSD:            current.HourlyCost(),
+				RecommendedHourlyUSD: target.HourlyCost(),
+			},
+			CostCurrentUSD: currentCost,
+			CostOptimalUSD: optimalCost,
+			CostSavingsUSD: currentCost - optimalCost,
+			DataPoints:     len(pts),
+		})
+	}
+
+	return out
+}
+
+// GenerateFargateFixActions emits a single task size change. CPU and memory
+// are changed together because Fargate only accepts specific pairs.
+func GenerateFargateFixActions(agg types.AggregatedMetrics) []types.FixAction {
+	m := agg.Machine
+	if m == nil || m.RecommendedType == m.InstanceType {
+		return []types.FixAction{}
+	}
+
+	return []types.FixAction{{
+		Provider: types.ProviderAWSECSFargate,
+		Resource: agg.Resource,
+		Intent:   "rightsize_task_size",
+		Description: fmt.Sprintf(
+			"Task size %g vCPU / %gGB → %g vCPU / %gGB (P95 CPU %.0f%%, memory %.0f%%)",
+			m.VCPU, m.MemoryGB, m.RecommendedVCPU, m.RecommendedMemoryGB,
+			agg.Metrics["cpu_percent"].P95, agg.Metrics["memory_percent"].P95,
+		),
+		Action: types.FixOperation{
+			Field:     "task_size",
+			Operation: "set_to",
+			Current:   m.VCPU,
+			Value:     m.RecommendedVCPU,
+			Unit:      "vCPU",
+			Target:    m.RecommendedType,
+		},
+		AIGuidance: fmt.Sprintf(
+			"Update the ECS task definition '%s' (task definition JSON or Terraform aws_ecs_task_definition). Set the task-level cpu to %.0f and memory to %.0f. Services pick it up on the next deployment.",
+			agg.Resource, m.RecommendedVCPU*1024, math.Round(m.RecommendedMemoryGB*1024),
+		),
+		EstimatedSavingsUSD: (m.HourlyUSD - m.RecommendedHourlyUSD) * agg.Metrics["running_tasks"].Avg * pricing.HoursPerMonth,
+		RiskLevel:           "medium",
+	}}
+}
@@ -0,0 +1,286 @@
+package aws
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/pricing"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/utils"
+)
+
+const (
+	RiskCategoryDatabase = "database"
+
+	rdsTargetCPUPercent    = 60.0
+	rdsTargetMemoryPercent = 80.0
+	rdsConnectionHeadroom  = 1.5
+	rdsIOPSHeadroom        = 1.3
+	rdsMinProvisionedIOPS  = 1000
+	rdsIOPSStep            = 100
+
+	// MySQL and PostgreSQL on RDS default max_connections to
+	// DBInstanceClassMemory/9531392, capped at 5000.
+	rdsBytesPerConnection = 9531392
+	rdsMaxConnections     = 5000
+)
+
+func maxConnections(memGB float64) float64 {
+	return math.Min(math.Floor(memGB*(1<<30)/rdsBytesPerConnection), rdsMaxConnections)
+}
+
+// AggregateRDS sizes database instances conservatively: memory is judged by
+// what is not freeable (buffer cache counts as used), and the default
+// max_connections of the smaller class must still cover peak connections.
+func AggregateRDS(resources map[string][]types.MetricCollection) []types.AggregatedMetrics {
+	out := []types.AggregatedMetrics{}
+	classes := pricing.Default.AWS.RDS.InstanceClasses
+
+	for name, pts := range resources {
+		sort.Slice(pts, func(i, j int) bool {
+			return pts[i].TimeStamp < pts[j].TimeStamp
+		})
+
+		latest := pts[len(pts)-1].Metrics.RDSResourceMetrics
+		class, known := classes[latest.InstanceClass]
+
+		cpuVals := make([]float64, 0, len(pts))
+		memVals := make([]float64, 0, len(pts))
+		freeVals := make([]float64, 0, len(pts))
+		connVals := make([]float64, 0, len(pts))
+		iopsVals := make([]float64, 0, len(pts))
+
+		for _, p := range pts {
+			m := p.Metrics.RDSResourceMetrics
+			cpuVals = append(cpuVals, m.CpuPercent)
+			freeVals = append(freeVals, m.FreeableMemoryGB)
+			if class.MemoryGB > 0 {
+				memVals = append(memVals, math.Max(class.MemoryGB-m.FreeableMemoryGB, 0)/class.MemoryGB*100)
+			}
+			connVals = append(connVals, m.Connections)
+			iopsVals = append(iopsVals, m.ReadIOPS+m.WriteIOPS)
+		}
+
+		metrics := map[string]types.MetricStat{
+			"cpu_percent":        utils.ComputeStat(cpuVals),
+			"memory_percent":     utils.ComputeStat(memVals),
+			"freeable_memory_gb": utils.ComputeStat(freeVals),
+			"connections":        utils.ComputeStat(connVals),
+			"iops":               utils.ComputeStat(iopsVals),
+		}
+
+		machine := &types.MachineInfo{
+			InstanceType:        latest.InstanceClass,
+			VCPU:                class.VCPU,
+			MemoryGB:            class.MemoryGB,
+			RecommendedType:     latest.InstanceClass,
+			RecommendedVCPU:     class.VCPU,
+			RecommendedMemoryGB: class.MemoryGB,
+
+			HourlyUSD:            class.HourlyUSD,
+			RecommendedHourlyUSD: class.HourlyUSD,
+		}
+		recommended := class
+
+		if known {
+			neededCPU := class.VCPU * metrics["cpu_percent"].P95 / rdsTargetCPUPercent
+			neededMem := math.Max(
+				class.MemoryGB*metrics["memory_percent"].P95/rdsTargetMemoryPercent,
+				metrics["connections"].P95*rdsConnectionHeadroom*rdsBytesPerConnection/(1<<30),
+			)
+			if n, m := pricing.CheapestFit(classes, math.Max(neededCPU, 1), neededMem, pricing.Series(latest.InstanceClass)); n != "" && m.HourlyUSD < class.HourlyUSD {
+				recommended = m
+				machine.RecommendedType = n
+				machine.RecommendedVCPU = m.VCPU
+				machine.RecommendedMemoryGB = m.MemoryGB
+				machine.RecommendedHourlyUSD = m.HourlyUSD
+			}
+		}
+
+		db := recommendRDSStorage(latest, metrics["iops"])
+		db.MaxConnections = maxConnections(class.MemoryGB)
+		db.RecommendedMaxConnections = maxConnections(recommended.MemoryGB)
+
+		currentCost := rdsCost(class, db.StorageType, db.AllocatedStorageGB, db.ProvisionedIOPS, db.MultiAZ)
+		optimalCost := rdsCost(recommended, db.RecommendedStorageType, db.AllocatedStorageGB, db.RecommendedIOPS, db.MultiAZ)
+
+		out = append(out, types.AggregatedMetrics{
+			Provider:       types.ProviderAWSRDS,
+			Resource:       name,
+			Metrics:        metrics,
+			Machine:        machine,
+			Database:       db,
+			CostCurrentUSD: currentCost,
+			CostOptimalUSD: optimalCost,
+			CostSavingsUSD: currentCost - optimalCost,
+			DataPoints:     len(pts),
+		})
+	}
+
+	return out
+}
+
+// recommendRDSStorage moves gp2 and io1 volumes whose P95 IOPS fit in the
+// gp3 baseline to gp3, and otherwise trims provisioned IOPS to P95 plus
+// headroom.
+func recommendRDSStorage(cfg types.RDSResourceMetrics, iops types.MetricStat) *types.DatabaseInfo {
+	db := &types.DatabaseInfo{
+		MultiAZ:                cfg.MultiAZ,
+		StorageType:            cfg.StorageType,
+		RecommendedStorageType: cfg.StorageType,
+		AllocatedStorageGB:     cfg.AllocatedStorageGB,
+		ProvisionedIOPS:        cfg.ProvisionedIOPS,
+		RecommendedIOPS:        cfg.ProvisionedIOPS,
+	}
+
+	storage := pricing.Default.AWS.RDS.Storage
+	gp3Baseline := storage["gp3"].BaselineIOPS
+	needed := math.Ceil(iops.P95*rdsIOPSHeadroom/rdsIOPSStep) * rdsIOPSStep
+
+	switch cfg.StorageType {
+	case "gp2", "io1":
+		if needed <= gp3Baseline {
+			db.RecommendedStorageType = "gp3"
+			db.RecommendedIOPS = 0
+			return db
+		}
+	}
+
+	if cfg.ProvisionedIOPS > 0 {
+		floor := float64(rdsMinProvisionedIOPS)
+		if cfg.StorageType == "gp3" {
+			floor = gp3Baseline
+		}
+		db.RecommendedIOPS = math.Min(math.Max(needed, floor), cfg.ProvisionedIOPS)
+	}
+
+	return db
+}
+
+func rdsStorageCost(storageType string, gb, iops float64) float64 {
+	s := pricing.Default.AWS.RDS.Storage[storageType]
+	return gb*s.PerGBMonth + math.Max(iops-s.BaselineIOPS, 0)*s.PerIOPSMonth
+}
+
+// rdsCost is the monthly instance and storage bill. Multi-AZ keeps a standby
+// of the same size, doubling both.
+func rdsCost(class pricing.MachineType, storageType string, gb, iops float64, multiAZ bool) float64 {
+	cost := class.HourlyUSD*pricing.HoursPerMonth + rdsStorageCost(storageType, gb, iops)
+	if multiAZ {
+		cost *= 2
+	}
+	return cost
+}
+
+// GenerateRDSFixActions emits instance class, storage type and IOPS changes
+// against Terraform aws_db_instance. Every action needs explicit approval:
+// a class change restarts the database (or fails over when Multi-AZ) and
+// storage changes leave the volume optimising for hours.
+func GenerateRDSFixActions(agg types.AggregatedMetrics) []types.FixAction {
+	out := []types.FixAction{}
+
+	m, db := agg.Machine, agg.Database
+	if m == nil || db == nil {
+		return out
+	}
+
+	mult := 1.0
+	if db.MultiAZ {
+		mult = 2
+	}
+
+	if m.RecommendedType != m.InstanceType {
+		savings := (m.HourlyUSD - m.RecommendedHourlyUSD) * pricing.HoursPerMonth * mult
+
+		restart := "The instance restarts during the change"
+		if db.MultiAZ {
+			restart = "The standby is resized first, then RDS fails over"
+		}
+
+		out = append(out, rdsAction(agg, "rightsize_instance_class", "instance_class",
+			fmt.Sprintf(
+				"Instance class %s (%g vCPU, %gGB) → %s (%g vCPU, %gGB), P95 CPU %.0f%%, P95 connections %.0f of %.0f. %s.",
+				m.InstanceType, m.VCPU, m.MemoryGB, m.RecommendedType, m.RecommendedVCPU, m.RecommendedMemoryGB,
+				agg.Metrics["cpu_percent"].P95, agg.Metrics["connections"].P95, db.RecommendedMaxConnections, restart,
+			),
+			types.FixOperation{
+				Current: m.VCPU,
+				Value:   m.RecommendedVCPU,
+				Unit:    "vCPU",
+				Target:  m.RecommendedType,
+			},
+			savings, "high",
+		))
+	}
+
+	if db.RecommendedStorageType != db.StorageType {
+		savings := (rdsStorageCost(db.StorageType, db.AllocatedStorageGB, db.ProvisionedIOPS) -
+			rdsStorageCost(db.RecommendedStorageType, db.AllocatedStorageGB, db.RecommendedIOPS)) * mult
+
+		out = append(out, rdsAction(agg, "change_storage_type", "storage_type",
+			fmt.Sprintf(
+				"Storage %s → %s (%.0fGB, P95 %.0f IOPS within the %s baseline). Remove any iops setting.",
+				db.StorageType, db.RecommendedStorageType, db.AllocatedStorageGB,
+				agg.Metrics["iops"].P95, db.RecommendedStorageType,
+			),
+			types.FixOperation{
+				Current: db.AllocatedStorageGB,
+				Value:   db.AllocatedStorageGB,
+				Unit:    "GB",
+				Target:  db.RecommendedStorageType,
+			},
+			savings, "medium",
+		))
+	} else if db.RecommendedIOPS < db.ProvisionedIOPS {
+		savings := (rdsStorageCost(db.StorageType, db.AllocatedStorageGB, db.ProvisionedIOPS) -
+			rdsStorageCost(db.StorageType, db.AllocatedStorageGB, db.RecommendedIOPS)) * mult
+
+		out = append(out, rdsAction(agg, "reduce_provisioned_iops", "iops",
+			fmt.Sprintf(
+				"Provisioned IOPS %.0f → %.0f (P95 %.0f IOPS). Peaks above the new limit are throttled.",
+				db.ProvisionedIOPS, db.RecommendedIOPS, agg.Metrics["iops"].P95,
+			),
+			types.FixOperation{
+				Current: db.ProvisionedIOPS,
+				Value:   db.RecommendedIOPS,
+				Unit:    "IOPS",
+			},
+			savings, "high",
+		))
+	}
+
+	return out
+}
+
+func rdsAction(
+	agg types.AggregatedMetrics,
+	intent, field, description string,
+	op types.FixOperation,
+	savings float64,
+	risk string,
+) types.FixAction {
+	op.Field = field
+	op.Operation = "set_to"
+
+	value := op.Target
+	if value == "" {
+		value = fmt.Sprintf("%g", op.Value)
+	}
+
+	return types.FixAction{
+		Provider:    types.ProviderAWSRDS,
+		Resource:    agg.Resource,
+		Intent:      intent,
+		Description: description,
+		Action:      op,
+		AIGuidance: fmt.Sprintf(
+			"Update the Terraform aws_db_instance '%s'. Set %s to %s and keep apply_immediately false so the change waits for the maintenance window.",
+			agg.Resource, field, value,
+		),
+		EstimatedSavingsUSD: savings,
+		RiskLevel:           risk,
+		RiskCategory:        RiskCategoryDatabase,
+		RequiresApproval:    true,
+	}
+}
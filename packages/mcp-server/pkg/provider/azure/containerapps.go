@@ -0,0 +1,205 @@
+package azure
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/pricing"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/utils"
+)
+
+const (
+	containerAppsHeadroom          = 1.2
+	containerAppsTargetUtilization = 60.0
+	containerAppsCPUStep           = 0.25
+	containerAppsMinCPU            = 0.25
+	// Consumption workload profiles only accept 2GiB of memory per vCPU.
+	containerAppsMemoryPerCPU = 2.0
+)
+
+func AggregateContainerApps(resources map[string][]types.MetricCollection) []types.AggregatedMetrics {
+	out := []types.AggregatedMetrics{}
+
+	for name, pts := range resources {
+		sort.Slice(pts, func(i, j int) bool {
+			return pts[i].TimeStamp < pts[j].TimeStamp
+		})
+
+		replicas := make([]float64, 0, len(pts))
+		cpuUsage := make([]float64, 0, len(pts))
+		memUsage := make([]float64, 0, len(pts))
+		vcpuSeconds, gibSeconds := 0.0, 0.0
+
+		for i, p := range pts {
+			m := p.Metrics.ContainerAppsResourceMetrics
+			replicas = append(replicas, m.Replicas)
+			cpuUsage = append(cpuUsage, m.CpuUsage)
+			memUsage = append(memUsage, m.MemoryUsageGB)
+
+			// The first sample's counters cover time before the window.
+			if i > 0 {
+				vcpuSeconds += m.VCPUSeconds
+				gibSeconds += m.GiBSeconds
+			}
+		}
+
+		metrics := map[string]types.MetricStat{
+			"replicas":        utils.ComputeStat(replicas),
+			"cpu_usage":       utils.ComputeStat(cpuUsage),
+			"memory_usage_gb": utils.ComputeStat(memUsage),
+		}
+
+		// Billed allocation per second of the window. Without counters it is
+		// approximated from the replica count and the configured size.
+		latest := pts[len(pts)-1].Metrics.ContainerAppsResourceMetrics
+		vcpu := metrics["replicas"].Avg * latest.CpuLimit
+		gib := metrics["replicas"].Avg * latest.MemoryLimitGB
+		if span := float64(pts[len(pts)-1].TimeStamp - pts[0].TimeStamp); span > 0 && vcpuSeconds > 0 {
+			vcpu = vcpuSeconds / span
+			gib = gibSeconds / span
+		}
+		metrics["vcpu"] = types.MetricStat{Avg: vcpu}
+		metrics["gib"] = types.MetricStat{Avg: gib}
+
+		info := RecommendContainerApp(latest, metrics)
+
+		agg := types.AggregatedMetrics{
+			Provider:       types.ProviderAzureContainerApps,
+			Resource:       name,
+			Metrics:        metrics,
+			Serverless:     &info,
+			CostCurrentUSD: ContainerAppsCost(vcpu, gib),
+			DataPoints:     len(pts),
+		}
+
+		savings := 0.0
+		for _, a := range GenerateContainerAppsFixActions(agg) {
+			savings += a.EstimatedSavingsUSD
+		}
+		agg.CostSavingsUSD = savings
+		agg.CostOptimalUSD = agg.CostCurrentUSD - savings
+
+		out = append(out, agg)
+	}
+
+	return out
+}
+
+// RecommendContainerApp sizes the container to P95 usage plus headroom on the
+// 0.25 vCPU / 0.5GiB grid Container Apps accepts, and never grows an app.
+func RecommendContainerApp(cfg types.ContainerAppsResourceMetrics, metrics map[string]types.MetricStat) types.ServerlessInfo {
+	info := types.ServerlessInfo{
+		CpuLimit:      cfg.CpuLimit,
+		MemoryLimitGB: cfg.MemoryLimitGB,
+		MinInstances:  cfg.MinReplicas,
+	}
+
+	neededCPU := math.Max(
+		metrics["cpu_usage"].P95*containerAppsHeadroom,
+		metrics["memory_usage_gb"].P95*containerAppsHeadroom/containerAppsMemoryPerCPU,
+	)
+	recCPU := math.Max(math.Ceil(neededCPU/containerAppsCPUStep)*containerAppsCPUStep, containerAppsMinCPU)
+	if recCPU >= cfg.CpuLimit {
+		recCPU = cfg.CpuLimit
+	}
+	info.RecommendedCpuLimit = recCPU
+	info.RecommendedMemoryLimitGB = math.Min(recCPU*containerAppsMemoryPerCPU, cfg.MemoryLimitGB)
+
+	// Replicas beyond what the median load keeps busy sit idle but are still
+	// billed at the idle rate.
+	utilization := 0.0
+	if cfg.CpuLimit > 0 {
+		utilization = metrics["cpu_usage"].P50 / cfg.CpuLimit * 100
+	}
+	recMin := math.Ceil(metrics["replicas"].P50 * math.Min(1, utilization/containerAppsTargetUtilization))
+	info.RecommendedMinInstances = math.Min(recMin, cfg.MinReplicas)
+
+	return info
+}
+
+func ContainerAppsCost(vcpu, gib float64) float64 {
+	p := pricing.Default.Azure.ContainerApps
+	return (vcpu*p.VCPUSecond + gib*p.GiBSecond) * pricing.SecondsPerMonth
+}
+
+func idleReplicaRate(cpu, memGB float64) float64 {
+	p := pricing.Default.Azure.ContainerApps
+	return cpu*p.IdleVCPUSecond + memGB*p.IdleGiBSecond
+}
+
+// GenerateContainerAppsFixActions emits CPU, memory and minReplicas changes.
+// CPU and memory savings scale the billed allocation by the size reduction.
+func GenerateContainerAppsFixActions(agg types.AggregatedMetrics) []types.FixAction {
+	out := []types.FixAction{}
+
+	info := agg.Serverless
+	if info == nil {
+		return out
+	}
+	p := pricing.Default.Azure.ContainerApps
+
+	if info.RecommendedCpuLimit < info.CpuLimit {
+		out = append(out, containerAppAction(agg, "rightsize_cpu",
+			"properties.template.containers.resources.cpu",
+			info.CpuLimit, info.RecommendedCpuLimit, "vCPU",
+			fmt.Sprintf("CPU %g → %g vCPU (P95 usage %.2f vCPU)",
+				info.CpuLimit, info.RecommendedCpuLimit, agg.Metrics["cpu_usage"].P95),
+			agg.Metrics["vcpu"].Avg*(1-info.RecommendedCpuLimit/info.CpuLimit)*p.VCPUSecond*pricing.SecondsPerMonth,
+		))
+	}
+
+	if info.RecommendedMemoryLimitGB < info.MemoryLimitGB {
+		out = append(out, containerAppAction(agg, "rightsize_memory",
+			"properties.template.containers.resources.memory",
+			info.MemoryLimitGB, info.RecommendedMemoryLimitGB, "Gi",
+			fmt.Sprintf("Memory %gGi → %gGi (P95 usage %.2fGB)",
+				info.MemoryLimitGB, info.RecommendedMemoryLimitGB, agg.Metrics["memory_usage_gb"].P95),
+			agg.Metrics["gib"].Avg*(1-info.RecommendedMemoryLimitGB/info.MemoryLimitGB)*p.GiBSecond*pricing.SecondsPerMonth,
+		))
+	}
+
+	if info.RecommendedMinInstances < info.MinInstances {
+		a := containerAppAction(agg, "reduce_min_replicas",
+			"properties.template.scale.minReplicas",
+			info.MinInstances, info.RecommendedMinInstances, "replicas",
+			fmt.Sprintf("minReplicas %.0f → %.0f (median %.1f replicas)",
+				info.MinInstances, info.RecommendedMinInstances, agg.Metrics["replicas"].P50),
+			(info.MinInstances-info.RecommendedMinInstances)*pricing.SecondsPerMonth*
+				idleReplicaRate(info.RecommendedCpuLimit, info.RecommendedMemoryLimitGB),
+		)
+		a.RiskLevel = "medium"
+		out = append(out, a)
+	}
+
+	return out
+}
+
+func containerAppAction(
+	agg types.AggregatedMetrics,
+	intent, field string,
+	current, value float64,
+	unit, description string,
+	savings float64,
+) types.FixAction {
+	return types.FixAction{
+		Provider:    types.ProviderAzureContainerApps,
+		Resource:    agg.Resource,
+		Intent:      intent,
+		Description: description,
+		Action: types.FixOperation{
+			Field:     field,
+			Operation: "set_to",
+			Current:   current,
+			Value:     value,
+			Unit:      unit,
+		},
+		AIGuidance: fmt.Sprintf(
+			"Update the Bicep, ARM or Container Apps YAML definition of '%s'. Set %s to %g.",
+			agg.Resource, field, value,
+		),
+		EstimatedSavingsUSD: math.Max(savings, 0),
+		RiskLevel:           "low",
+	}
+}
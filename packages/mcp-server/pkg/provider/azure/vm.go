@@ -0,0 +1,16 @@
+package azure
+
+import (
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/pricing"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/provider/compute"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+func AggregateVMs(resources map[string][]types.MetricCollection) []types.AggregatedMetrics {
+	return compute.AggregateVMs(types.ProviderAzureVM, pricing.Default.Azure.VMSizes, resources)
+}
+
+func GenerateVMFixActions(agg types.AggregatedMetrics) []types.FixAction {
+	return compute.GenerateMachineFixActions(agg, "vmSize",
+		"Update the Bicep or ARM template of virtual machine '%s'. Set hardwareProfile.vmSize to '%s'. The VM is deallocated to resize it.")
+}
@@ -0,0 +1,162 @@
+package compute
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/pricing"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/provider/network"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/utils"
+)
+
+const (
+	vmTargetCPUPercent    = 80.0
+	vmTargetMemoryPercent = 85.0
+)
+
+// AggregateVMs rightsizes fixed-size virtual machines from their CPU and
+// memory utilisation against the given machine catalog. NetworkGB is billed
+// as internet egress on top of the machine price.
+func AggregateVMs(
+	provider types.Provider,
+	machines map[string]pricing.MachineType,
+	resources map[string][]types.MetricCollection,
+) []types.AggregatedMetrics {
+	out := []types.AggregatedMetrics{}
+
+	for name, pts := range resources {
+		sort.Slice(pts, func(i, j int) bool {
+			return pts[i].TimeStamp < pts[j].TimeStamp
+		})
+
+		cpuVals := make([]float64, 0, len(pts))
+		memVals := make([]float64, 0, len(pts))
+		for _, p := range pts {
+			cpuVals = append(cpuVals, p.Metrics.VMResourceMetrics.CpuPercent)
+			memVals = append(memVals, p.Metrics.VMResourceMetrics.MemoryPercent)
+		}
+
+		metrics := map[string]types.MetricStat{
+			"cpu_percent":    utils.ComputeStat(cpuVals),
+			"memory_percent": utils.ComputeStat(memVals),
+		}
+
+		var net *types.NetworkInfo
+		if span := float64(pts[len(pts)-1].TimeStamp - pts[0].TimeStamp); span > 0 {
+			gb := 0.0
+			for _, p := range pts[1:] {
+				gb += p.Metrics.VMResourceMetrics.NetworkGB
+			}
+			if gb > 0 {
+				gb *= pricing.SecondsPerMonth / span
+				net = &types.NetworkInfo{InternetGB: gb, CostUSD: network.InternetEgressCost(gb)}
+				metrics["network_gb"] = types.MetricStat{Avg: gb}
+			}
+		}
+
+		machineType := pts[len(pts)-1].Metrics.VMResourceMetrics.InstanceType
+		info, current, recommended := RecommendMachine(
+			machines, machineType,
+			metrics["cpu_percent"], metrics["memory_percent"],
+		)
+
+		currentCost := current.HourlyUSD * pricing.HoursPerMonth
+		optimalCost := recommended.HourlyUSD * pricing.HoursPerMonth
+		if net != nil {
+			currentCost += net.CostUSD
+			optimalCost += net.CostUSD
+		}
+
+		out = append(out, types.AggregatedMetrics{
+			Provider:       provider,
+			Resource:       name,
+			Metrics:        metrics,
+			Machine:        info,
+			Network:        net,
+			CostCurrentUSD: currentCost,
+			CostOptimalUSD: optimalCost,
+			CostSavingsUSD: currentCost - optimalCost,
+			DataPoints:     len(pts),
+		})
+	}
+
+	return out
+}
+
+// RecommendMachine picks the cheapest machine type that keeps P95 CPU and
+// memory under their targets, preferring the current series. Memory is held
+// at the current size when no memory utilisation was reported. Unknown
+// machine types are left as they are.
+func RecommendMachine(
+	machines map[string]pricing.MachineType,
+	machineType string,
+	cpu, mem types.MetricStat,
+) (*types.MachineInfo, pricing.MachineType, pricing.MachineType) {
+	current, ok := machines[machineType]
+	info := &types.MachineInfo{
+		InstanceType:        machineType,
+		VCPU:                current.VCPU,
+		MemoryGB:            current.MemoryGB,
+		RecommendedType:     machineType,
+		RecommendedVCPU:     current.VCPU,
+		RecommendedMemoryGB: current.MemoryGB,
+
+		HourlyUSD:            current.HourlyUSD,
+		RecommendedHourlyUSD: current.HourlyUSD,
+	}
+	if !ok {
+		return info, current, current
+	}
+
+	neededCPU := current.VCPU * cpu.P95 / vmTargetCPUPercent
+	neededMem := current.MemoryGB
+	if mem.P95 > 0 {
+		neededMem = current.MemoryGB * mem.P95 / vmTargetMemoryPercent
+	}
+
+	name, recommended := pricing.CheapestFit(machines, math.Max(neededCPU, 1), neededMem, pricing.Series(machineType))
+	if name == "" || recommended.HourlyUSD >= current.HourlyUSD {
+		return info, current, current
+	}
+
+	info.RecommendedType = name
+	info.RecommendedVCPU = recommended.VCPU
+	info.RecommendedMemoryGB = recommended.MemoryGB
+	info.RecommendedHourlyUSD = recommended.HourlyUSD
+
+	return info, current, recommended
+}
+
+// GenerateMachineFixActions emits a machine type change when a smaller type
+// was recommended. guidance is formatted with the resource and new type.
+func GenerateMachineFixActions(agg types.AggregatedMetrics, field, guidance string) []types.FixAction {
+	m := agg.Machine
+	if m == nil || m.RecommendedType == m.InstanceType {
+		return []types.FixAction{}
+	}
+
+	return []types.FixAction{{
+		Provider: agg.Provider,
+		Resource: agg.Resource,
+		Intent:   "rightsize_machine_type",
+		Description: fmt.Sprintf(
+			"Machine type %s (%g vCPU, %gGB) → %s (%g vCPU, %gGB), P95 CPU %.0f%%",
+			m.InstanceType, m.VCPU, m.MemoryGB,
+			m.RecommendedType, m.RecommendedVCPU, m.RecommendedMemoryGB,
+			agg.Metrics["cpu_percent"].P95,
+		),
+		Action: types.FixOperation{
+			Field:     field,
+			Operation: "set_to",
+			Current:   m.VCPU,
+			Value:     m.RecommendedVCPU,
+			Unit:      "vCPU",
+			Target:    m.RecommendedType,
+		},
+		AIGuidance:          fmt.Sprintf(guidance, agg.Resource, m.RecommendedType),
+		EstimatedSavingsUSD: (m.HourlyUSD - m.RecommendedHourlyUSD) * pricing.HoursPerMonth,
+		RiskLevel:           "medium",
+	}}
+}
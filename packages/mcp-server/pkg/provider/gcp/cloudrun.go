@@ -0,0 +1,241 @@
+package gcp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/pricing"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/utils"
+)
+
+const (
+	cloudRunHeadroom           = 1.2
+	cloudRunTargetUtilization  = 60.0
+	cloudRunDefaultConcurrency = 80
+	cloudRunMemoryStepGB       = 0.25
+)
+
+// cloudRunCPUs are the CPU limits Cloud Run accepts for request-based
+// billing, with the minimum memory each one requires.
+var cloudRunCPUs = []struct {
+	cpu      float64
+	minMemGB float64
+}{
+	{1, 0.5},
+	{2, 0.5},
+	{4, 2},
+	{6, 4},
+	{8, 4},
+}
+
+func AggregateCloudRun(resources map[string][]types.MetricCollection) []types.AggregatedMetrics {
+	out := []types.AggregatedMetrics{}
+
+	for name, pts := range resources {
+		sort.Slice(pts, func(i, j int) bool {
+			return pts[i].TimeStamp < pts[j].TimeStamp
+		})
+
+		rps := make([]float64, 0, len(pts))
+		instances := make([]float64, 0, len(pts))
+		cpuUtil := make([]float64, 0, len(pts))
+		memUtil := make([]float64, 0, len(pts))
+
+		for _, p := range pts {
+			m := p.Metrics.CloudRunResourceMetrics
+			rps = append(rps, m.RequestsPerSecond)
+			instances = append(instances, m.Instances)
+			cpuUtil = append(cpuUtil, m.CpuUtilization)
+			memUtil = append(memUtil, m.MemoryUtilization)
+		}
+
+		metrics := map[string]types.MetricStat{
+			"requests_per_second": utils.ComputeStat(rps),
+			"instances":           utils.ComputeStat(instances),
+			"cpu_utilization":     utils.ComputeStat(cpuUtil),
+			"memory_utilization":  utils.ComputeStat(memUtil),
+		}
+
+		// Configuration is taken from the most recent sample.
+		latest := pts[len(pts)-1].Metrics.CloudRunResourceMetrics
+		info := RecommendCloudRun(latest, metrics)
+
+		agg := types.AggregatedMetrics{
+			Provider:       types.ProviderGCPCloudRun,
+			Resource:       name,
+			Metrics:        metrics,
+			Serverless:     &info,
+			CostCurrentUSD: CloudRunCost(info.CpuLimit, info.MemoryLimitGB, metrics),
+			DataPoints:     len(pts),
+		}
+
+		savings := 0.0
+		for _, a := range GenerateCloudRunFixActions(agg) {
+			savings += a.EstimatedSavingsUSD
+		}
+		agg.CostSavingsUSD = savings
+		agg.CostOptimalUSD = agg.CostCurrentUSD - savings
+
+		out = append(out, agg)
+	}
+
+	return out
+}
+
+// RecommendCloudRun sizes limits to P95 utilisation plus headroom, snapped to
+// the CPU and memory values Cloud Run accepts, and never grows a service.
+func RecommendCloudRun(cfg types.CloudRunResourceMetrics, metrics map[string]types.MetricStat) types.ServerlessInfo {
+	cpuUtil := metrics["cpu_utilization"]
+	memUtil := metrics["memory_utilization"]
+	instances := metrics["instances"]
+
+	info := types.ServerlessInfo{
+		CpuLimit:      cfg.CpuLimit,
+		MemoryLimitGB: cfg.MemoryLimitGB,
+		MinInstances:  cfg.MinInstances,
+		Concurrency:   cfg.Concurrency,
+	}
+
+	neededCPU := cfg.CpuLimit * cpuUtil.P95 / 100 * cloudRunHeadroom
+	recCPU, minMem := cfg.CpuLimit, 0.0
+	for _, c := range cloudRunCPUs {
+		if c.cpu >= neededCPU {
+			recCPU, minMem = c.cpu, c.minMemGB
+			break
+		}
+	}
+	info.RecommendedCpuLimit = math.Min(recCPU, cfg.CpuLimit)
+
+	// Without memory utilisation the current limit is kept.
+	info.RecommendedMemoryLimitGB = cfg.MemoryLimitGB
+	if memUtil.P95 > 0 {
+		neededMem := cfg.MemoryLimitGB * memUtil.P95 / 100 * cloudRunHeadroom
+		recMem := math.Max(math.Ceil(neededMem/cloudRunMemoryStepGB)*cloudRunMemoryStepGB, minMem)
+		info.RecommendedMemoryLimitGB = math.Min(recMem, cfg.MemoryLimitGB)
+	}
+
+	// Warm instances beyond what the median load keeps busy are idle spend.
+	recMin := math.Ceil(instances.P50 * math.Min(1, cpuUtil.P50/cloudRunTargetUtilization))
+	info.RecommendedMinInstances = math.Min(recMin, cfg.MinInstances)
+
+	info.RecommendedConcurrency = cfg.Concurrency
+	if cfg.Concurrency > 0 && cfg.Concurrency < cloudRunDefaultConcurrency && cpuUtil.P95 < cloudRunTargetUtilization {
+		raised := math.Floor(cfg.Concurrency * cloudRunTargetUtilization / math.Max(cpuUtil.P95, 1))
+		info.RecommendedConcurrency = math.Min(raised, cloudRunDefaultConcurrency)
+	}
+
+	return info
+}
+
+func instanceRate(cpu, memGB float64) float64 {
+	p := pricing.Default.GCP.CloudRun
+	return cpu*p.VCPUSecond + memGB*p.GiBSecond
+}
+
+func idleInstanceRate(cpu, memGB float64) float64 {
+	p := pricing.Default.GCP.CloudRun
+	return cpu*p.IdleVCPUSecond + memGB*p.IdleGiBSecond
+}
+
+// CloudRunCost is the monthly bill for the observed instance time and
+// request volume at the given limits.
+func CloudRunCost(cpu, memGB float64, metrics map[string]types.MetricStat) float64 {
+	p := pricing.Default.GCP.CloudRun
+	instanceCost := metrics["instances"].Avg * pricing.SecondsPerMonth * instanceRate(cpu, memGB)
+	requestCost := metrics["requests_per_second"].Avg * pricing.SecondsPerMonth / 1e6 * p.PerMillionRequests
+	return instanceCost + requestCost
+}
+
+// GenerateCloudRunFixActions emits limit, concurrency and minScale changes.
+// Each action's savings assume the ones before it were applied, so they add
+// up to the service's total.
+func GenerateCloudRunFixActions(agg types.AggregatedMetrics) []types.FixAction {
+	out := []types.FixAction{}
+
+	info := agg.Serverless
+	if info == nil {
+		return out
+	}
+	instances := agg.Metrics["instances"].Avg
+
+	if info.RecommendedCpuLimit < info.CpuLimit {
+		out = append(out, cloudRunAction(agg, "rightsize_cpu_limit",
+			"spec.template.spec.containers.resources.limits.cpu",
+			info.CpuLimit, info.RecommendedCpuLimit, "vCPU",
+			fmt.Sprintf("CPU limit %g → %g vCPU (P95 utilisation %.0f%%)",
+				info.CpuLimit, info.RecommendedCpuLimit, agg.Metrics["cpu_utilization"].P95),
+			instances*pricing.SecondsPerMonth*(instanceRate(info.CpuLimit, info.MemoryLimitGB)-
+				instanceRate(info.RecommendedCpuLimit, info.MemoryLimitGB)),
+			"low",
+		))
+	}
+
+	if info.RecommendedMemoryLimitGB < info.MemoryLimitGB {
+		out = append(out, cloudRunAction(agg, "rightsize_memory_limit",
+			"spec.template.spec.containers.resources.limits.memory",
+			info.MemoryLimitGB, info.RecommendedMemoryLimitGB, "GB",
+			fmt.Sprintf("Memory limit %.2fGB → %.2fGB (P95 utilisation %.0f%%)",
+				info.MemoryLimitGB, info.RecommendedMemoryLimitGB, agg.Metrics["memory_utilization"].P95),
+			instances*pricing.SecondsPerMonth*(instanceRate(info.RecommendedCpuLimit, info.MemoryLimitGB)-
+				instanceRate(info.RecommendedCpuLimit, info.RecommendedMemoryLimitGB)),
+			"low",
+		))
+	}
+
+	rate := instanceRate(info.RecommendedCpuLimit, info.RecommendedMemoryLimitGB)
+
+	if info.RecommendedConcurrency > info.Concurrency {
+		out = append(out, cloudRunAction(agg, "raise_concurrency",
+			"spec.template.spec.containerConcurrency",
+			info.Concurrency, info.RecommendedConcurrency, "requests",
+			fmt.Sprintf("Container concurrency %.0f → %.0f", info.Concurrency, info.RecommendedConcurrency),
+			instances*(1-info.Concurrency/info.RecommendedConcurrency)*pricing.SecondsPerMonth*rate,
+			"medium",
+		))
+	}
+
+	if info.RecommendedMinInstances < info.MinInstances {
+		out = append(out, cloudRunAction(agg, "reduce_min_instances",
+			"spec.template.metadata.annotations.minScale",
+			info.MinInstances, info.RecommendedMinInstances, "instances",
+			fmt.Sprintf("minScale %.0f → %.0f (median %.1f instances)",
+				info.MinInstances, info.RecommendedMinInstances, agg.Metrics["instances"].P50),
+			(info.MinInstances-info.RecommendedMinInstances)*pricing.SecondsPerMonth*
+				idleInstanceRate(info.RecommendedCpuLimit, info.RecommendedMemoryLimitGB),
+			"medium",
+		))
+	}
+
+	return out
+}
+
+func cloudRunAction(
+	agg types.AggregatedMetrics,
+	intent, field string,
+	current, value float64,
+	unit, description string,
+	savings float64,
+	risk string,
+) types.FixAction {
+	return types.FixAction{
+		Provider:    types.ProviderGCPCloudRun,
+		Resource:    agg.Resource,
+		Intent:      intent,
+		Description: description,
+		Action: types.FixOperation{
+			Field:     field,
+			Operation: "set_to",
+			Current:   current,
+			Value:     value,
+			Unit:      unit,
+		},
+		AIGuidance: fmt.Sprintf(
+			"Update the Cloud Run service YAML for '%s'. Set %s to %g.",
+			agg.Resource, field, value,
+		),
+		EstimatedSavingsUSD: savings,
+		RiskLevel:           risk,
+	}
+}
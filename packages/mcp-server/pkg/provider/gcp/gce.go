@@ -0,0 +1,16 @@
+package gcp
+
+import (
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/pricing"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/provider/compute"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+func AggregateGCE(resources map[string][]types.MetricCollection) []types.AggregatedMetrics {
+	return compute.AggregateVMs(types.ProviderGCPGCE, pricing.Default.GCP.MachineTypes, resources)
+}
+
+func GenerateGCEFixActions(agg types.AggregatedMetrics) []types.FixAction {
+	return compute.GenerateMachineFixActions(agg, "machine_type",
+		"Update the Terraform google_compute_instance '%s'. Set machine_type to \"%s\". The instance restarts to apply it.")
+}
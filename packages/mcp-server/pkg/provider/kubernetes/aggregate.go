@@ -11,6 +11,9 @@ import (
 func Aggregate(
 	resources map[string][]types.MetricCollection,
 	actualReqs map[string]types.Requests,
+	autoscalers map[string]types.HPAConfig,
+	workloads map[string]types.WorkloadInfo,
+	rec Recommender,
 ) []types.AggregatedMetrics {
 	out := []types.AggregatedMetrics{}
 
@@ -41,21 +44,57 @@ func Aggregate(
 
 		reqCPU, reqMem := ResolveRequests(name, cpuStat, memStat, actualReqs)
 
-		optimalCPU, optimalMem := OptimalRequests(cpuStat, memStat)
+		usage := UsageProfile{CPU: cpuStat, Memory: memStat, Samples: pts}
+		optimal := rec.Recommend(usage)
+
+		var bounds *types.RecommendationBounds
+		if br, ok := rec.(BoundsRecommender); ok {
+			b := br.RecommendBounds(usage)
+			bounds = &b
+		}
+
+		metrics := map[string]types.MetricStat{
+			"cpu_milli": cpuStat,
+			"memory_gb": memStat,
+		}
+		for k, v := range replicaStats(pts) {
+			metrics[k] = v
+		}
+
+		// Costs cover every replica, so replica and idle savings are
+		// measured against the same bill as rightsizing.
+		replicas := ObservedReplicas(metrics)
+		currentCost := ComputeCostFromRequests(reqCPU, reqMem) * replicas
+		optimalCost := ComputeCostFromRequests(optimal.CpuMilli, optimal.MemoryGB) * replicas
+
+		idle := DetectIdle(pts)
+		if idle != nil {
+			optimalCost = 0
+		}
+
+		var autoscaler *types.HPAConfig
+		if hpa, ok := autoscalers[name]; ok {
+			autoscaler = &hpa
+		}
+
+		var workload *types.WorkloadInfo
+		if w, ok := workloads[name]; ok {
+			workload = &w
+		}
 
-		currentCost := ComputeCostFromRequests(reqCPU, reqMem)
-		optimalCost := ComputeCostFromRequests(optimalCPU, optimalMem)
 		out = append(out, types.AggregatedMetrics{
-			Provider: types.ProviderKubernetes,
-			Resource: name,
-			Metrics: map[string]types.MetricStat{
-				"cpu_milli": cpuStat,
-				"memory_gb": memStat,
-			},
+			Provider:          types.ProviderKubernetes,
+			Resource:          name,
+			Metrics:           metrics,
+			Autoscaler:        autoscaler,
+			Idle:              idle,
+			Workload:          workload,
 			RequestedCpuMilli: reqCPU,
 			RequestedMemoryGB: reqMem,
-			OptimalCpuMilli:   optimalCPU,
-			OptimalMemoryGB:   optimalMem,
+			OptimalCpuMilli:   optimal.CpuMilli,
+			OptimalMemoryGB:   optimal.MemoryGB,
+			Recommender:       rec.Name(),
+			Bounds:            bounds,
 			CostCurrentUSD:    currentCost,
 			CostOptimalUSD:    optimalCost,
 			CostSavingsUSD:    currentCost - optimalCost,
@@ -1,17 +1,15 @@
 package kubernetes
 
-import "github.com/tanay13/costguard/packages/mcp-server/pkg/types"
-
-const (
-	cpuRatePerMilli = 0.00001
-	memRatePerGB    = 0.00002
-)
+import "github.com/tanay13/costguard/packages/mcp-server/pkg/pricing"
 
 func ComputeCostFromRequests(cpuMilli float64, memGB float64) float64 {
-	return cpuMilli*cpuRatePerMilli*24*30 +
-		memGB*memRatePerGB*24*30
+	return CPUCost(cpuMilli) + MemoryCost(memGB)
+}
+
+func CPUCost(cpuMilli float64) float64 {
+	return cpuMilli * pricing.Default.Kubernetes.CPUPerMilliHour * pricing.HoursPerMonth
 }
 
-func OptimalRequests(cpu types.MetricStat, mem types.MetricStat) (float64, float64) {
-	return cpu.P50 * 1.2, mem.P50 * 1.2
+func MemoryCost(memGB float64) float64 {
+	return memGB * pricing.Default.Kubernetes.MemoryPerGBHour * pricing.HoursPerMonth
 }
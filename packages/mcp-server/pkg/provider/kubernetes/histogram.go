@@ -0,0 +1,71 @@
+package kubernetes
+
+import "math"
+
+const (
+	histogramBucketGrowth = 1.05
+	histogramMaxBuckets   = 400
+)
+
+// decayingHistogram buckets values on an exponential scale starting at
+// firstBucket, and weights each sample by 2^(-age/halfLife) so recent usage
+// dominates the distribution.
+type decayingHistogram struct {
+	firstBucket float64
+	halfLife    float64
+	weights     []float64
+	total       float64
+}
+
+func newDecayingHistogram(firstBucket, halfLifeSeconds float64) *decayingHistogram {
+	return &decayingHistogram{
+		firstBucket: firstBucket,
+		halfLife:    halfLifeSeconds,
+		weights:     make([]float64, histogramMaxBuckets),
+	}
+}
+
+func (h *decayingHistogram) add(value, ageSeconds float64) {
+	if value < 0 {
+		return
+	}
+
+	w := math.Exp2(-ageSeconds / h.halfLife)
+	h.weights[h.bucket(value)] += w
+	h.total += w
+}
+
+func (h *decayingHistogram) bucket(value float64) int {
+	if value < h.firstBucket {
+		return 0
+	}
+
+	b := int(math.Log(value/h.firstBucket)/math.Log(histogramBucketGrowth)) + 1
+	if b >= histogramMaxBuckets {
+		b = histogramMaxBuckets - 1
+	}
+	return b
+}
+
+// bucketEnd is the upper bound of bucket b, which is what the histogram
+// reports as the percentile value.
+func (h *decayingHistogram) bucketEnd(b int) float64 {
+	return h.firstBucket * math.Pow(histogramBucketGrowth, float64(b))
+}
+
+func (h *decayingHistogram) percentile(p float64) float64 {
+	if h.total == 0 {
+		return 0
+	}
+
+	threshold := p * h.total
+	acc := 0.0
+	for b, w := range h.weights {
+		acc += w
+		if acc >= threshold {
+			return h.bucketEnd(b)
+		}
+	}
+
+	return h.bucketEnd(len(h.weights) - 1)
+}
@@ -0,0 +1,138 @@
+package kubernetes
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+const (
+	idleCPUMilli         = 5
+	idleMinWindowSeconds = 7 * 24 * 60 * 60
+	idleLastActiveLimit  = 5
+)
+
+// DetectIdle reports a resource as idle when its trailing stretch of inactive
+// samples spans at least a week. Workloads that report traffic are judged on
+// requests served (zero traffic makes them zombies); the rest on CPU usage
+// staying under idleCPUMilli.
+func DetectIdle(pts []types.MetricCollection) *types.IdleReport {
+	if len(pts) == 0 {
+		return nil
+	}
+
+	sorted := make([]types.MetricCollection, len(pts))
+	copy(sorted, pts)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].TimeStamp < sorted[j].TimeStamp
+	})
+
+	reportsTraffic := false
+	for _, p := range sorted {
+		if p.Metrics.K8sResourceMetrics.RequestCount != nil {
+			reportsTraffic = true
+			break
+		}
+	}
+
+	lastActive := []int64{}
+	for i := len(sorted) - 1; i >= 0 && len(lastActive) < idleLastActiveLimit; i-- {
+		if isActive(sorted[i], reportsTraffic) {
+			lastActive = append(lastActive, sorted[i].TimeStamp)
+		}
+	}
+
+	idleSince := sorted[0].TimeStamp
+	if len(lastActive) > 0 {
+		idleSince = lastActive[0]
+	}
+
+	end := sorted[len(sorted)-1].TimeStamp
+	if end-idleSince < idleMinWindowSeconds {
+		return nil
+	}
+
+	days := math.Floor(float64(end-idleSince) / (24 * 60 * 60))
+	reason := fmt.Sprintf("CPU below %dm for %.0f days", idleCPUMilli, days)
+	if reportsTraffic {
+		reason = fmt.Sprintf("no traffic for %.0f days", days)
+	}
+
+	return &types.IdleReport{
+		Zombie:     reportsTraffic,
+		Reason:     reason,
+		IdleSince:  idleSince,
+		LastActive: lastActive,
+	}
+}
+
+func isActive(p types.MetricCollection, reportsTraffic bool) bool {
+	m := p.Metrics.K8sResourceMetrics
+	if reportsTraffic {
+		return m.RequestCount != nil && *m.RequestCount > 0
+	}
+	return m.CpuMilli >= idleCPUMilli
+}
+
+// GenerateIdleFixActions replaces rightsizing for idle resources: idle ones
+// are scaled to zero and zombies are deleted. Both are always high-risk and
+// wait for explicit approval.
+func GenerateIdleFixActions(agg types.AggregatedMetrics) []types.FixAction {
+	idle := agg.Idle
+	if idle == nil {
+		return nil
+	}
+
+	replicas := 1.0
+	if r, ok := agg.Metrics["replicas"]; ok && r.P95 > 0 {
+		replicas = math.Round(r.P95)
+	}
+	// Priced on observed replicas, like the workload's scan cost.
+	savings := ComputeCostFromRequests(agg.RequestedCpuMilli, agg.RequestedMemoryGB) * ObservedReplicas(agg.Metrics)
+
+	lastSeen := "never"
+	if len(idle.LastActive) > 0 {
+		lastSeen = time.Unix(idle.LastActive[0], 0).UTC().Format(time.RFC3339)
+	}
+
+	action := types.FixAction{
+		Provider:            types.ProviderKubernetes,
+		Resource:            agg.Resource,
+		EstimatedSavingsUSD: savings,
+		RiskLevel:           "high",
+		RequiresApproval:    true,
+		LastActive:          idle.LastActive,
+	}
+
+	if idle.Zombie {
+		action.Intent = "delete"
+		action.Description = fmt.Sprintf("Delete workload: %s (last active: %s)", idle.Reason, lastSeen)
+		action.Action = types.FixOperation{
+			Operation: "delete",
+			Current:   replicas,
+		}
+		action.AIGuidance = fmt.Sprintf(
+			"Confirm with the owners of '%s' that it is unused, then remove its Kubernetes manifests.",
+			agg.Resource,
+		)
+	} else {
+		action.Intent = "scale_to_zero"
+		action.Description = fmt.Sprintf("Scale to zero: %s (last active: %s)", idle.Reason, lastSeen)
+		action.Action = types.FixOperation{
+			Field:     "spec.replicas",
+			Operation: "set_to",
+			Current:   replicas,
+			Value:     0,
+			Unit:      "replicas",
+		}
+		action.AIGuidance = fmt.Sprintf(
+			"Confirm '%s' is idle, then set spec.replicas to 0 in its Kubernetes manifest.",
+			agg.Resource,
+		)
+	}
+
+	return []types.FixAction{action}
+}
@@ -7,24 +7,20 @@ import (
 	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
 )
 
-func GenerateK8sFixActions(agg types.AggregatedMetrics) []types.FixAction {
+func GenerateK8sFixActions(agg types.AggregatedMetrics, rec Recommender) []types.FixAction {
+	if agg.Idle != nil {
+		return GenerateIdleFixActions(agg)
+	}
+
 	out := []types.FixAction{}
 
 	reqCPU := agg.RequestedCpuMilli
 	reqMem := agg.RequestedMemoryGB
 
-	cpu := agg.Metrics["cpu"]
-	mem := agg.Metrics["memory"]
-
-	optCPU := cpu.P95 * 1.2
-	if optCPU < 50 {
-		optCPU = 50
-	}
-
-	optMem := mem.P95 * 1.1
-	if optMem < 0.1 {
-		optMem = 0.1
-	}
+	optimal := RecommendedRequests(agg, rec)
+	optCPU := optimal.CpuMilli
+	optMem := optimal.MemoryGB
+	replicas := ObservedReplicas(agg.Metrics)
 
 	cpuPercent := ((optCPU - reqCPU) / reqCPU) * 100
 	if math.Abs(cpuPercent) > 5 {
@@ -39,6 +35,7 @@ func GenerateK8sFixActions(agg types.AggregatedMetrics) []types.FixAction {
 			Action: types.FixOperation{
 				Field:     "resources.requests.cpu",
 				Operation: "set_to",
+				Current:   reqCPU,
 				Value:     optCPU,
 				Unit:      "m",
 			},
@@ -46,6 +43,7 @@ func GenerateK8sFixActions(agg types.AggregatedMetrics) []types.FixAction {
 				"Update the Kubernetes manifest for '%s'. Set CPU request to %.0fm.",
 				agg.Resource, optCPU,
 			),
+			EstimatedSavingsUSD: (CPUCost(reqCPU) - CPUCost(optCPU)) * replicas,
 		})
 	}
 
@@ -62,6 +60,7 @@ func GenerateK8sFixActions(agg types.AggregatedMetrics) []types.FixAction {
 			Action: types.FixOperation{
 				Field:     "resources.requests.memory",
 				Operation: "set_to",
+				Current:   reqMem,
 				Value:     optMem,
 				Unit:      "GB",
 			},
@@ -69,8 +68,31 @@ func GenerateK8sFixActions(agg types.AggregatedMetrics) []types.FixAction {
 				"Update the Kubernetes manifest for '%s'. Set memory request to %.2fGB.",
 				agg.Resource, optMem,
 			),
+			EstimatedSavingsUSD: (MemoryCost(reqMem) - MemoryCost(optMem)) * replicas,
 		})
 	}
 
+	out = append(out, GenerateReplicaFixActions(agg, optimal)...)
+	out = append(out, GeneratePlacementFixActions(agg)...)
+
 	return out
 }
+
+// RecommendedRequests reuses the values computed at scan time unless a
+// different recommender is asked for, in which case they are re-derived from
+// the aggregated stats. A nil rec means "whatever the scan used".
+func RecommendedRequests(agg types.AggregatedMetrics, rec Recommender) types.Requests {
+	if agg.OptimalCpuMilli > 0 && agg.OptimalMemoryGB > 0 &&
+		(rec == nil || agg.Recommender == rec.Name()) {
+		return types.Requests{CpuMilli: agg.OptimalCpuMilli, MemoryGB: agg.OptimalMemoryGB}
+	}
+
+	if rec == nil {
+		rec, _ = NewRecommender(DefaultStrategy)
+	}
+
+	return rec.Recommend(UsageProfile{
+		CPU:    agg.Metrics["cpu_milli"],
+		Memory: agg.Metrics["memory_gb"],
+	})
+}
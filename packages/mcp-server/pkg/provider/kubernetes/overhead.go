@@ -0,0 +1,101 @@
+package kubernetes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+// DistributeOverhead charges the part of the node bill that workload
+// requests don't cover to the workloads. Shared workloads (listed ones and
+// DaemonSets) and idle capacity are split by the cluster's strategy:
+// proportionally to request cost, evenly, or by custom weights. Afterwards
+// every workload's AllocatedCostUSD sums to the node cost.
+func DistributeOverhead(agg []types.AggregatedMetrics, c types.ClusterCost) (types.ClusterSummary, error) {
+	strategy := c.Strategy
+	if strategy == "" {
+		strategy = types.OverheadProportional
+	}
+	switch strategy {
+	case types.OverheadProportional, types.OverheadEven, types.OverheadWeighted:
+	default:
+		return types.ClusterSummary{}, fmt.Errorf("unknown overhead strategy %q: use %s, %s or %s",
+			strategy, types.OverheadProportional, types.OverheadEven, types.OverheadWeighted)
+	}
+
+	nodeCost := c.NodeCostUSD
+	if nodeCost == 0 {
+		for _, n := range c.Nodes {
+			nodeCost += float64(n.Count) * n.MonthlyPriceUSD
+		}
+	}
+	if nodeCost <= 0 {
+		return types.ClusterSummary{}, fmt.Errorf("cluster node cost is unknown: give nodes with monthly_price_usd or node_cost_usd")
+	}
+
+	shared := map[string]bool{}
+	for _, r := range c.SharedResources {
+		shared[r] = true
+	}
+
+	s := types.ClusterSummary{Strategy: strategy, NodeCostUSD: nodeCost}
+	tenants := []int{}
+	requestCost := make([]float64, len(agg))
+	for i, a := range agg {
+		if a.Provider != types.ProviderKubernetes {
+			continue
+		}
+		// Only the requests of every running replica occupy nodes; any
+		// network cost attributed to the workload is billed elsewhere.
+		requestCost[i] = ComputeCostFromRequests(a.RequestedCpuMilli, a.RequestedMemoryGB) * ObservedReplicas(a.Metrics)
+		if shared[a.Resource] || (a.Workload != nil && strings.EqualFold(a.Workload.Kind, "DaemonSet")) {
+			s.SharedCostUSD += requestCost[i]
+			agg[i].Overhead = &types.OverheadInfo{Shared: true}
+			continue
+		}
+		s.WorkloadCostUSD += requestCost[i]
+		tenants = append(tenants, i)
+	}
+	s.IdleCostUSD = nodeCost - s.WorkloadCostUSD - s.SharedCostUSD
+
+	if len(tenants) == 0 {
+		return s, fmt.Errorf("no workloads to distribute cluster overhead to")
+	}
+
+	weights := make([]float64, len(tenants))
+	total := 0.0
+	for j, i := range tenants {
+		switch strategy {
+		case types.OverheadProportional:
+			weights[j] = requestCost[i]
+		case types.OverheadEven:
+			weights[j] = 1
+		case types.OverheadWeighted:
+			weights[j] = max(c.Weights[agg[i].Resource], 0)
+		}
+		total += weights[j]
+	}
+	if total == 0 {
+		if strategy == types.OverheadWeighted {
+			return s, fmt.Errorf("overhead weights match no workload")
+		}
+		// Workloads without requests have no cost to be proportional to.
+		for j := range weights {
+			weights[j] = 1
+		}
+		total = float64(len(weights))
+	}
+
+	for j, i := range tenants {
+		share := weights[j] / total
+		o := &types.OverheadInfo{
+			SharedCostUSD: s.SharedCostUSD * share,
+			IdleCostUSD:   s.IdleCostUSD * share,
+		}
+		o.AllocatedCostUSD = requestCost[i] + o.SharedCostUSD + o.IdleCostUSD
+		agg[i].Overhead = o
+	}
+
+	return s, nil
+}
@@ -0,0 +1,122 @@
+package kubernetes
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+func overheadWorkloads() []types.AggregatedMetrics {
+	return []types.AggregatedMetrics{
+		{
+			Provider:          types.ProviderKubernetes,
+			Resource:          "api",
+			RequestedCpuMilli: 1000,
+			RequestedMemoryGB: 2,
+			Metrics:           map[string]types.MetricStat{"replicas": {Avg: 3, P95: 4}},
+		},
+		{
+			Provider:          types.ProviderKubernetes,
+			Resource:          "web",
+			RequestedCpuMilli: 1000,
+			RequestedMemoryGB: 2,
+		},
+		{
+			Provider:          types.ProviderKubernetes,
+			Resource:          "ingress",
+			RequestedCpuMilli: 500,
+			RequestedMemoryGB: 1,
+		},
+		{
+			Provider:          types.ProviderKubernetes,
+			Resource:          "node-exporter",
+			RequestedCpuMilli: 100,
+			RequestedMemoryGB: 0.1,
+			Workload:          &types.WorkloadInfo{Kind: "DaemonSet"},
+		},
+		{Provider: types.ProviderAWSRDS, Resource: "orders", CostCurrentUSD: 300},
+	}
+}
+
+func TestDistributeOverhead(t *testing.T) {
+	pod := ComputeCostFromRequests(1000, 2)
+	shared := ComputeCostFromRequests(500, 1) + ComputeCostFromRequests(100, 0.1)
+	nodeCost := 1000.0
+
+	tests := []struct {
+		strategy string
+		weights  map[string]float64
+		// apiShare is api's share of shared and idle cost.
+		apiShare float64
+	}{
+		{types.OverheadProportional, nil, 0.75},
+		{types.OverheadEven, nil, 0.5},
+		{types.OverheadWeighted, map[string]float64{"api": 1, "web": 3}, 0.25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			aggs := overheadWorkloads()
+			s, err := DistributeOverhead(aggs, types.ClusterCost{
+				NodeCostUSD:     nodeCost,
+				SharedResources: []string{"ingress"},
+				Strategy:        tt.strategy,
+				Weights:         tt.weights,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// api runs three replicas on average, so it occupies three pods'
+			// worth of nodes.
+			if want := 4 * pod; math.Abs(s.WorkloadCostUSD-want) > 1e-9 {
+				t.Errorf("WorkloadCostUSD = %.4f, want %.4f", s.WorkloadCostUSD, want)
+			}
+			if math.Abs(s.SharedCostUSD-shared) > 1e-9 {
+				t.Errorf("SharedCostUSD = %.4f, want %.4f", s.SharedCostUSD, shared)
+			}
+			if want := nodeCost - 4*pod - shared; math.Abs(s.IdleCostUSD-want) > 1e-9 {
+				t.Errorf("IdleCostUSD = %.4f, want %.4f", s.IdleCostUSD, want)
+			}
+
+			allocated := 0.0
+			for _, a := range aggs {
+				if a.Overhead != nil {
+					allocated += a.Overhead.AllocatedCostUSD
+				}
+			}
+			if math.Abs(allocated-nodeCost) > 1e-9 {
+				t.Errorf("allocated %.4f in total, want the node cost %.4f", allocated, nodeCost)
+			}
+
+			api := aggs[0].Overhead
+			if want := 3*pod + (s.SharedCostUSD+s.IdleCostUSD)*tt.apiShare; math.Abs(api.AllocatedCostUSD-want) > 1e-9 {
+				t.Errorf("api AllocatedCostUSD = %.4f, want %.4f", api.AllocatedCostUSD, want)
+			}
+			if !aggs[2].Overhead.Shared || !aggs[3].Overhead.Shared {
+				t.Error("ingress and the DaemonSet should be marked shared")
+			}
+			if aggs[4].Overhead != nil {
+				t.Error("non-Kubernetes resources should not get overhead")
+			}
+		})
+	}
+}
+
+func TestDistributeOverheadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		c    types.ClusterCost
+	}{
+		{"unknown strategy", types.ClusterCost{NodeCostUSD: 100, Strategy: "random"}},
+		{"no node cost", types.ClusterCost{}},
+		{"weights match nothing", types.ClusterCost{NodeCostUSD: 100, Strategy: types.OverheadWeighted, Weights: map[string]float64{"other": 1}}},
+	}
+
+	for _, tt := range tests {
+		if _, err := DistributeOverhead(overheadWorkloads(), tt.c); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
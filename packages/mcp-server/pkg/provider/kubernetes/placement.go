@@ -0,0 +1,131 @@
+package kubernetes
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/pricing"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+const (
+	RiskCategoryPlacement = "placement"
+
+	minSpotReplicas = 2
+)
+
+// SpotEligible reports whether a workload can ride out spot interruptions:
+// a stateless Deployment with enough replicas that losing one node does not
+// take it down, and a PodDisruptionBudget guarding voluntary evictions.
+func SpotEligible(w types.WorkloadInfo, replicas int) (bool, string) {
+	switch {
+	case w.OnSpot:
+		return false, "already on spot"
+	case w.Kind != "Deployment":
+		return false, fmt.Sprintf("%s is not a stateless Deployment", w.Kind)
+	case replicas < minSpotReplicas:
+		return false, fmt.Sprintf("only %d replica(s)", replicas)
+	case !w.HasPDB:
+		return false, "no PodDisruptionBudget"
+	}
+	return true, ""
+}
+
+// GeneratePlacementFixActions moves interruption-tolerant workloads to spot
+// capacity, and otherwise to the cheapest node pool in the pricing catalog.
+// Workloads with persistent volumes stay put: their claims may be bound to
+// zones the other pool doesn't cover, and nothing here checks capacity.
+func GeneratePlacementFixActions(agg types.AggregatedMetrics) []types.FixAction {
+	out := []types.FixAction{}
+
+	if agg.Workload == nil {
+		return out
+	}
+	w := *agg.Workload
+	k8s := pricing.Default.Kubernetes
+
+	replicas := w.Replicas
+	if r, ok := agg.Metrics["replicas"]; ok && replicas == 0 {
+		replicas = int(math.Round(r.P95))
+	}
+	workloadCost := ComputeCostFromRequests(agg.RequestedCpuMilli, agg.RequestedMemoryGB) * float64(max(replicas, 1))
+
+	if ok, _ := SpotEligible(w, replicas); ok && k8s.SpotDiscount > 0 {
+		out = append(out, types.FixAction{
+			Provider: types.ProviderKubernetes,
+			Resource: agg.Resource,
+			Intent:   "move_to_spot",
+			Description: fmt.Sprintf(
+				"Run %d replicas on spot capacity (%.0f%% discount)",
+				replicas, k8s.SpotDiscount*100,
+			),
+			Action: types.FixOperation{
+				Field:     "spec.template.spec.nodeSelector",
+				Operation: "add_placement",
+				Target:    "spot",
+			},
+			AIGuidance: fmt.Sprintf(
+				"Update the Kubernetes manifest for '%s'. Add nodeSelector %s: %s and a toleration for %s=%s:NoSchedule to the pod template.",
+				agg.Resource, k8s.SpotNodeSelector.Key, k8s.SpotNodeSelector.Value,
+				k8s.SpotToleration.Key, k8s.SpotToleration.Value,
+			),
+			EstimatedSavingsUSD: workloadCost * k8s.SpotDiscount,
+			RiskLevel:           "medium",
+			RiskCategory:        RiskCategoryPlacement,
+		})
+		return out
+	}
+
+	if w.Kind == "StatefulSet" || w.HasVolumes {
+		return out
+	}
+
+	currentPool := w.NodePool
+	if currentPool == "" {
+		currentPool = "default"
+	}
+	currentPrice, known := k8s.NodePools[currentPool]
+	if !known {
+		return out
+	}
+
+	pools := make([]string, 0, len(k8s.NodePools))
+	for pool := range k8s.NodePools {
+		pools = append(pools, pool)
+	}
+	sort.Strings(pools)
+
+	cheapest, cheapestPrice := currentPool, currentPrice
+	for _, pool := range pools {
+		if k8s.NodePools[pool] < cheapestPrice {
+			cheapest, cheapestPrice = pool, k8s.NodePools[pool]
+		}
+	}
+
+	if cheapest != currentPool {
+		out = append(out, types.FixAction{
+			Provider: types.ProviderKubernetes,
+			Resource: agg.Resource,
+			Intent:   "move_to_node_pool",
+			Description: fmt.Sprintf(
+				"Move from node pool '%s' to '%s' (%.0f%% cheaper)",
+				currentPool, cheapest, (1-cheapestPrice/currentPrice)*100,
+			),
+			Action: types.FixOperation{
+				Field:     "spec.template.spec.nodeSelector",
+				Operation: "add_placement",
+				Target:    cheapest,
+			},
+			AIGuidance: fmt.Sprintf(
+				"Update the Kubernetes manifest for '%s'. Set nodeSelector %s: %s on the pod template.",
+				agg.Resource, k8s.NodePoolLabel, cheapest,
+			),
+			EstimatedSavingsUSD: workloadCost * (1 - cheapestPrice/currentPrice),
+			RiskLevel:           "medium",
+			RiskCategory:        RiskCategoryPlacement,
+		})
+	}
+
+	return out
+}
@@ -0,0 +1,48 @@
+package kubernetes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var memorySuffixes = []struct {
+	suffix string
+	bytes  float64
+}{
+	{"Ki", 1 << 10}, {"Mi", 1 << 20}, {"Gi", 1 << 30}, {"Ti", 1 << 40},
+	{"k", 1e3}, {"K", 1e3}, {"M", 1e6}, {"G", 1e9}, {"T", 1e12},
+}
+
+// ParseCPUMilli parses a CPU quantity such as "250m", "0.5" or "2".
+func ParseCPUMilli(q string) (float64, error) {
+	q = strings.Trim(strings.TrimSpace(q), `"'`)
+	if v, ok := strings.CutSuffix(q, "m"); ok {
+		return strconv.ParseFloat(v, 64)
+	}
+	v, err := strconv.ParseFloat(q, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cpu quantity %q", q)
+	}
+	return v * 1000, nil
+}
+
+// ParseMemoryGB parses a memory quantity such as "512Mi", "1Gi", "1G" or a
+// plain byte count into the GiB units used throughout CostGuard.
+func ParseMemoryGB(q string) (float64, error) {
+	q = strings.Trim(strings.TrimSpace(q), `"'`)
+	for _, s := range memorySuffixes {
+		if v, ok := strings.CutSuffix(q, s.suffix); ok {
+			n, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid memory quantity %q", q)
+			}
+			return n * s.bytes / (1 << 30), nil
+		}
+	}
+	n, err := strconv.ParseFloat(q, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid memory quantity %q", q)
+	}
+	return n / (1 << 30), nil
+}
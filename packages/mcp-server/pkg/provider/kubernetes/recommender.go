@@ -0,0 +1,82 @@
+package kubernetes
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+const (
+	StrategyConservative = "conservative"
+	StrategyBalanced     = "balanced"
+	StrategyAggressive   = "aggressive"
+	StrategyVPA          = "vpa"
+
+	DefaultStrategy = StrategyBalanced
+
+	minCPUMilli = 50
+	minMemoryGB = 0.1
+)
+
+// UsageProfile is everything a Recommender may look at for one resource.
+// Samples is empty when only aggregated statistics are available, e.g. when
+// a fix plan is built from a saved scan.
+type UsageProfile struct {
+	CPU     types.MetricStat
+	Memory  types.MetricStat
+	Samples []types.MetricCollection
+}
+
+// Recommender turns observed usage into recommended container requests. Scan
+// reports and fix plans both go through a Recommender so they always agree
+// on the target values.
+type Recommender interface {
+	Name() string
+	Recommend(usage UsageProfile) types.Requests
+}
+
+func NewRecommender(name string) (Recommender, error) {
+	switch name {
+	case "", DefaultStrategy:
+		return percentileRecommender{name: StrategyBalanced, cpuP95: 1.2, memP95: 1.1}, nil
+	case StrategyConservative:
+		return percentileRecommender{name: StrategyConservative, cpuP95: 1.5, memP95: 1.3}, nil
+	case StrategyAggressive:
+		return percentileRecommender{name: StrategyAggressive, cpuP50: 1.2, memP50: 1.2}, nil
+	case StrategyVPA:
+		return vpaRecommender{}, nil
+	default:
+		return nil, fmt.Errorf("unknown recommender %q (want one of %s, %s, %s, %s)",
+			name, StrategyConservative, StrategyBalanced, StrategyAggressive, StrategyVPA)
+	}
+}
+
+// percentileRecommender applies a fixed headroom multiplier to either the P50
+// or P95 of each resource. Exactly one of the P50/P95 multipliers is set per
+// resource.
+type percentileRecommender struct {
+	name   string
+	cpuP50 float64
+	cpuP95 float64
+	memP50 float64
+	memP95 float64
+}
+
+func (r percentileRecommender) Name() string {
+	return r.name
+}
+
+func (r percentileRecommender) Recommend(usage UsageProfile) types.Requests {
+	cpu := usage.CPU.P50*r.cpuP50 + usage.CPU.P95*r.cpuP95
+	mem := usage.Memory.P50*r.memP50 + usage.Memory.P95*r.memP95
+
+	return withFloors(cpu, mem)
+}
+
+func withFloors(cpuMilli, memGB float64) types.Requests {
+	return types.Requests{
+		CpuMilli: math.Max(cpuMilli, minCPUMilli),
+		MemoryGB: math.Max(memGB, minMemoryGB),
+	}
+}
@@ -0,0 +1,172 @@
+package kubernetes
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/utils"
+)
+
+const (
+	defaultTargetUtilization = 70.0
+	lowTargetUtilization     = 50.0
+	hpaMaxHeadroom           = 1.5
+)
+
+// replicaStats summarises replica counts over time and the CPU demand across
+// all replicas (per-pod usage × replicas). It returns nothing when the samples
+// carry no replica counts.
+func replicaStats(pts []types.MetricCollection) map[string]types.MetricStat {
+	replicas := make([]float64, 0, len(pts))
+	demand := make([]float64, 0, len(pts))
+
+	for _, p := range pts {
+		m := p.Metrics.K8sResourceMetrics
+		if m.Replicas <= 0 {
+			continue
+		}
+		replicas = append(replicas, m.Replicas)
+		demand = append(demand, m.CpuMilli*m.Replicas)
+	}
+
+	if len(replicas) == 0 {
+		return nil
+	}
+
+	return map[string]types.MetricStat{
+		"replicas":         utils.ComputeStat(replicas),
+		"cpu_demand_milli": utils.ComputeStat(demand),
+	}
+}
+
+// ObservedReplicas is the average number of replicas that ran, which is what
+// the bill reflects. It is 1 when the samples carry no replica counts.
+func ObservedReplicas(metrics map[string]types.MetricStat) float64 {
+	if r, ok := metrics["replicas"]; ok && r.Avg > 0 {
+		return r.Avg
+	}
+	return 1
+}
+
+// podsNeeded is how many pods at the given CPU request serve the given
+// total demand while staying at the target utilisation.
+func podsNeeded(demandMilli, reqCPU, targetUtilization float64) int {
+	if reqCPU <= 0 || targetUtilization <= 0 {
+		return 1
+	}
+	return max(1, int(math.Ceil(demandMilli/(reqCPU*targetUtilization/100))))
+}
+
+// GenerateReplicaFixActions recommends a lower static replica count for
+// resources without an autoscaler, or tighter minReplicas/maxReplicas and a
+// higher CPU target for resources behind an HPA. Pods are sized and priced at
+// the recommended requests, since the plan rightsizes those alongside: the
+// rightsizing actions save the request difference on every observed replica
+// and these save whole pods on top, so together they never exceed the
+// workload's cost.
+func GenerateReplicaFixActions(agg types.AggregatedMetrics, recommended types.Requests) []types.FixAction {
+	out := []types.FixAction{}
+
+	replicas, ok := agg.Metrics["replicas"]
+	if !ok {
+		return out
+	}
+	demand := agg.Metrics["cpu_demand_milli"]
+	pod := recommended
+	if pod.CpuMilli <= 0 {
+		pod = types.Requests{CpuMilli: agg.RequestedCpuMilli, MemoryGB: agg.RequestedMemoryGB}
+	}
+	podCost := ComputeCostFromRequests(pod.CpuMilli, pod.MemoryGB)
+	observed := ObservedReplicas(agg.Metrics)
+
+	if agg.Autoscaler == nil {
+		current := int(math.Round(replicas.P95))
+		needed := podsNeeded(demand.P95, pod.CpuMilli, defaultTargetUtilization)
+		if needed < current {
+			out = append(out, replicaAction(agg, "rightsize_replicas", "spec.replicas",
+				float64(current), float64(needed), "replicas",
+				fmt.Sprintf("Replicas %d → %d (P95 CPU demand %.0fm at %.0f%% target utilisation)",
+					current, needed, demand.P95, defaultTargetUtilization),
+				fmt.Sprintf("Update the Kubernetes manifest for '%s'. Set spec.replicas to %d.", agg.Resource, needed),
+				math.Max(observed-float64(needed), 0)*podCost,
+			))
+		}
+		return out
+	}
+
+	hpa := *agg.Autoscaler
+	target := hpa.TargetCPUUtilization
+	if target <= 0 {
+		target = defaultTargetUtilization
+	}
+
+	// The floor only costs money while it is binding, i.e. when the HPA
+	// spends most of its time sitting at minReplicas.
+	baseline := podsNeeded(demand.P50, pod.CpuMilli, target)
+	minSavings := 0.0
+	if hpa.MinReplicas > baseline {
+		if replicas.P50 <= float64(hpa.MinReplicas) {
+			minSavings = math.Max(math.Min(float64(hpa.MinReplicas), observed)-float64(baseline), 0) * podCost
+		}
+		out = append(out, replicaAction(agg, "tune_hpa_min_replicas", "spec.minReplicas",
+			float64(hpa.MinReplicas), float64(baseline), "replicas",
+			fmt.Sprintf("HPA minReplicas %d → %d (median CPU demand %.0fm)", hpa.MinReplicas, baseline, demand.P50),
+			fmt.Sprintf("Update the HorizontalPodAutoscaler for '%s'. Set minReplicas to %d.", agg.Resource, baseline),
+			minSavings,
+		))
+	}
+
+	peak := podsNeeded(demand.P95, pod.CpuMilli, target)
+	ceiling := max(baseline, int(math.Ceil(float64(peak)*hpaMaxHeadroom)))
+	if hpa.MaxReplicas > ceiling*2 {
+		out = append(out, replicaAction(agg, "tune_hpa_max_replicas", "spec.maxReplicas",
+			float64(hpa.MaxReplicas), float64(ceiling), "replicas",
+			fmt.Sprintf("HPA maxReplicas %d → %d (P95 needs %d pods)", hpa.MaxReplicas, ceiling, peak),
+			fmt.Sprintf("Update the HorizontalPodAutoscaler for '%s'. Set maxReplicas to %d.", agg.Resource, ceiling),
+			0,
+		))
+	}
+
+	if hpa.TargetCPUUtilization > 0 && hpa.TargetCPUUtilization < lowTargetUtilization {
+		// Pods already saved by a lower minReplicas can't be saved again.
+		savings := math.Min(
+			observed*(1-hpa.TargetCPUUtilization/defaultTargetUtilization)*podCost,
+			observed*podCost-minSavings,
+		)
+		out = append(out, replicaAction(agg, "tune_hpa_target_utilization",
+			"spec.metrics.resource.target.averageUtilization",
+			hpa.TargetCPUUtilization, defaultTargetUtilization, "%",
+			fmt.Sprintf("HPA CPU target %.0f%% → %.0f%%", hpa.TargetCPUUtilization, defaultTargetUtilization),
+			fmt.Sprintf("Update the HorizontalPodAutoscaler for '%s'. Set the CPU averageUtilization target to %.0f.",
+				agg.Resource, defaultTargetUtilization),
+			savings,
+		))
+	}
+
+	return out
+}
+
+func replicaAction(
+	agg types.AggregatedMetrics,
+	intent, field string,
+	current, value float64,
+	unit, description, guidance string,
+	savings float64,
+) types.FixAction {
+	return types.FixAction{
+		Provider:    types.ProviderKubernetes,
+		Resource:    agg.Resource,
+		Intent:      intent,
+		Description: description,
+		Action: types.FixOperation{
+			Field:     field,
+			Operation: "set_to",
+			Current:   current,
+			Value:     value,
+			Unit:      unit,
+		},
+		AIGuidance:          guidance,
+		EstimatedSavingsUSD: savings,
+	}
+}
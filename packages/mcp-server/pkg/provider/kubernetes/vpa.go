@@ -0,0 +1,167 @@
+package kubernetes
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+// Constants mirror the defaults of the Kubernetes Vertical Pod Autoscaler
+// recommender so the numbers line up with what the in-cluster controller
+// would produce from the same samples.
+const (
+	vpaCPUFirstBucketMilli = 10
+	vpaMemFirstBucketGB    = 0.01
+	vpaHalfLifeSeconds     = 24 * 60 * 60
+
+	vpaTargetPercentile = 0.9
+	vpaLowerPercentile  = 0.5
+	vpaUpperPercentile  = 0.95
+	vpaSafetyMargin     = 0.15
+
+	vpaMinCPUMilli = 25
+	vpaMinMemoryGB = 0.25
+
+	vpaMaxUpperScale = 4
+
+	vpaOOMMinBumpGB = 0.1
+	vpaOOMBumpRatio = 1.2
+
+	VPAModeOff     = "Off"
+	VPAModeInitial = "Initial"
+)
+
+// BoundsRecommender is implemented by recommenders that can also report a
+// range of acceptable requests around their target.
+type BoundsRecommender interface {
+	RecommendBounds(usage UsageProfile) types.RecommendationBounds
+}
+
+type VPARecommendation struct {
+	Target     types.Requests
+	LowerBound types.Requests
+	UpperBound types.Requests
+}
+
+// vpaRecommender weights recent samples more heavily through decaying
+// histograms, like the Kubernetes Vertical Pod Autoscaler. Without raw
+// samples it falls back to the balanced strategy.
+type vpaRecommender struct{}
+
+func (vpaRecommender) Name() string {
+	return StrategyVPA
+}
+
+func (vpaRecommender) Recommend(usage UsageProfile) types.Requests {
+	if len(usage.Samples) == 0 {
+		fallback, _ := NewRecommender(StrategyBalanced)
+		return fallback.Recommend(usage)
+	}
+
+	return RecommendVPA(usage.Samples).Target
+}
+
+func (vpaRecommender) RecommendBounds(usage UsageProfile) types.RecommendationBounds {
+	if len(usage.Samples) == 0 {
+		return types.RecommendationBounds{}
+	}
+
+	r := RecommendVPA(usage.Samples)
+	return types.RecommendationBounds{Lower: r.LowerBound, Upper: r.UpperBound}
+}
+
+// RecommendVPA builds CPU and memory histograms from the samples of a single
+// resource and derives target, lower and upper bounds from them. Samples
+// flagged as OOM-killed are bumped before being recorded so the memory
+// recommendation grows past the point where the container was killed.
+func RecommendVPA(samples []types.MetricCollection) VPARecommendation {
+	cpuHist := newDecayingHistogram(vpaCPUFirstBucketMilli, vpaHalfLifeSeconds)
+	memHist := newDecayingHistogram(vpaMemFirstBucketGB, vpaHalfLifeSeconds)
+
+	first, last := int64(math.MaxInt64), int64(math.MinInt64)
+	for _, s := range samples {
+		first = min(first, s.TimeStamp)
+		last = max(last, s.TimeStamp)
+	}
+
+	for _, s := range samples {
+		age := float64(last - s.TimeStamp)
+		m := s.Metrics.K8sResourceMetrics
+
+		mem := m.MemoryGB
+		if m.OOMKilled {
+			mem = math.Max(mem+vpaOOMMinBumpGB, mem*vpaOOMBumpRatio)
+		}
+
+		cpuHist.add(m.CpuMilli, age)
+		memHist.add(mem, age)
+	}
+
+	// Confidence grows with the length of the observed history, measured in
+	// days. Short histories widen the bounds the same way VPA does, though the
+	// upper bound is capped so a few minutes of data can't yield an unbounded
+	// maxAllowed.
+	confidence := float64(last-first) / (24 * 60 * 60)
+	lowerScale := 0.0
+	upperScale := float64(vpaMaxUpperScale)
+	if confidence > 0 {
+		lowerScale = math.Pow(1+0.001/confidence, -2)
+		upperScale = math.Min(1+1/confidence, vpaMaxUpperScale)
+	}
+
+	estimate := func(p, scale float64) types.Requests {
+		return types.Requests{
+			CpuMilli: math.Max(cpuHist.percentile(p)*(1+vpaSafetyMargin)*scale, vpaMinCPUMilli),
+			MemoryGB: math.Max(memHist.percentile(p)*(1+vpaSafetyMargin)*scale, vpaMinMemoryGB),
+		}
+	}
+
+	return VPARecommendation{
+		Target:     estimate(vpaTargetPercentile, 1),
+		LowerBound: estimate(vpaLowerPercentile, lowerScale),
+		UpperBound: estimate(vpaUpperPercentile, upperScale),
+	}
+}
+
+// RenderVPA produces a VerticalPodAutoscaler manifest for a workload. In
+// "Off" mode the controller only publishes recommendations; in "Initial" mode
+// it applies them when pods are created. The bounds, when known, become the
+// container policy's minAllowed/maxAllowed.
+func RenderVPA(resource, kind, mode string, bounds types.RecommendationBounds) (string, error) {
+	if mode != VPAModeOff && mode != VPAModeInitial {
+		return "", fmt.Errorf("unsupported VPA update mode %q (want %s or %s)", mode, VPAModeOff, VPAModeInitial)
+	}
+
+	if kind == "" {
+		kind = "Deployment"
+	}
+
+	var b strings.Builder
+	b.WriteString("apiVersion: autoscaling.k8s.io/v1\n")
+	b.WriteString("kind: VerticalPodAutoscaler\n")
+	b.WriteString("metadata:\n")
+	b.WriteString(fmt.Sprintf("  name: %s-vpa\n", resource))
+	b.WriteString("spec:\n")
+	b.WriteString("  targetRef:\n")
+	b.WriteString("    apiVersion: apps/v1\n")
+	b.WriteString(fmt.Sprintf("    kind: %s\n", kind))
+	b.WriteString(fmt.Sprintf("    name: %s\n", resource))
+	b.WriteString("  updatePolicy:\n")
+	b.WriteString(fmt.Sprintf("    updateMode: \"%s\"\n", mode))
+
+	if bounds.Lower.CpuMilli > 0 || bounds.Upper.CpuMilli > 0 {
+		b.WriteString("  resourcePolicy:\n")
+		b.WriteString("    containerPolicies:\n")
+		b.WriteString("      - containerName: \"*\"\n")
+		b.WriteString("        minAllowed:\n")
+		b.WriteString(fmt.Sprintf("          cpu: \"%.0fm\"\n", bounds.Lower.CpuMilli))
+		b.WriteString(fmt.Sprintf("          memory: \"%.0fMi\"\n", bounds.Lower.MemoryGB*1024))
+		b.WriteString("        maxAllowed:\n")
+		b.WriteString(fmt.Sprintf("          cpu: \"%.0fm\"\n", bounds.Upper.CpuMilli))
+		b.WriteString(fmt.Sprintf("          memory: \"%.0fMi\"\n", bounds.Upper.MemoryGB*1024))
+	}
+
+	return b.String(), nil
+}
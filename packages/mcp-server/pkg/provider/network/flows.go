@@ -0,0 +1,251 @@
+package network
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/pricing"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+const (
+	FlowInternet    = "internet"
+	FlowCrossRegion = "cross_region"
+	FlowCrossZone   = "cross_zone"
+	FlowSameZone    = "same_zone"
+
+	TopologyAnnotation = "service.kubernetes.io/topology-mode"
+
+	// A pair exchanging this much cross-zone traffic a month is worth
+	// routing in-zone.
+	chattyCrossZoneGB = 100.0
+	// Topology-aware routing still sends some traffic across zones to keep
+	// endpoints balanced, so only part of the cross-zone bill goes away.
+	topologyReduction = 0.7
+)
+
+// InternetEgressCost prices gb of traffic leaving the cloud.
+func InternetEgressCost(gb float64) float64 {
+	return gb * pricing.Default.Network.InternetEgressPerGB
+}
+
+// Classify decides how a flow is billed from the latest sample's placement.
+func Classify(m types.FlowResourceMetrics) string {
+	switch {
+	case m.Destination == "" || m.Destination == FlowInternet:
+		return FlowInternet
+	case m.SourceRegion != "" && m.DestinationRegion != "" && m.SourceRegion != m.DestinationRegion:
+		return FlowCrossRegion
+	case m.SourceZone != "" && m.DestinationZone != "" && m.SourceZone != m.DestinationZone:
+		return FlowCrossZone
+	default:
+		return FlowSameZone
+	}
+}
+
+func flowCost(kind string, gb float64) float64 {
+	p := pricing.Default.Network
+	switch kind {
+	case FlowInternet:
+		return gb * p.InternetEgressPerGB
+	case FlowCrossRegion:
+		return gb * p.CrossRegionPerGB
+	case FlowCrossZone:
+		return gb * p.CrossZonePerGB
+	default:
+		return 0
+	}
+}
+
+// AggregateFlows prices the traffic of every source resource, keyed by
+// source. Transfer is summed per destination and scaled to a month from the
+// sampled window.
+func AggregateFlows(resources map[string][]types.MetricCollection) map[string]*types.NetworkInfo {
+	out := map[string]*types.NetworkInfo{}
+
+	for source, pts := range resources {
+		byDest := map[string][]types.MetricCollection{}
+		for _, p := range pts {
+			dest := p.Metrics.FlowResourceMetrics.Destination
+			byDest[dest] = append(byDest[dest], p)
+		}
+
+		info := &types.NetworkInfo{}
+		for dest, samples := range byDest {
+			sort.Slice(samples, func(i, j int) bool {
+				return samples[i].TimeStamp < samples[j].TimeStamp
+			})
+
+			span := float64(samples[len(samples)-1].TimeStamp - samples[0].TimeStamp)
+			if span <= 0 {
+				continue
+			}
+
+			gb := 0.0
+			for _, s := range samples[1:] {
+				gb += s.Metrics.FlowResourceMetrics.TransferGB
+			}
+			gb *= pricing.SecondsPerMonth / span
+
+			kind := Classify(samples[len(samples)-1].Metrics.FlowResourceMetrics)
+			if dest == "" {
+				dest = FlowInternet
+			}
+
+			flow := types.NetworkFlow{
+				Destination:   dest,
+				Kind:          kind,
+				MonthlyGB:     gb,
+				CostUSD:       flowCost(kind, gb),
+				TopologyAware: kind == FlowCrossZone && gb >= chattyCrossZoneGB,
+			}
+
+			switch kind {
+			case FlowInternet:
+				info.InternetGB += gb
+			case FlowCrossRegion:
+				info.CrossRegionGB += gb
+			case FlowCrossZone:
+				info.CrossZoneGB += gb
+			}
+			info.CostUSD += flow.CostUSD
+			info.Flows = append(info.Flows, flow)
+		}
+
+		sort.Slice(info.Flows, func(i, j int) bool {
+			return info.Flows[i].CostUSD > info.Flows[j].CostUSD
+		})
+		out[source] = info
+	}
+
+	return out
+}
+
+// Attribute adds each source's network cost to the resource of the same
+// name, or reports it as a standalone network_flow resource when the source
+// was not scanned otherwise. Savings from topology-aware routing are counted
+// against the source that pays for the traffic.
+func Attribute(aggs []types.AggregatedMetrics, flows map[string]*types.NetworkInfo) []types.AggregatedMetrics {
+	sources := make([]string, 0, len(flows))
+	for source := range flows {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	for _, source := range sources {
+		info := flows[source]
+
+		idx := -1
+		for i := range aggs {
+			if aggs[i].Resource == source && aggs[i].Network == nil {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			aggs = append(aggs, types.AggregatedMetrics{
+				Provider: types.ProviderNetworkFlow,
+				Resource: source,
+				Metrics:  map[string]types.MetricStat{},
+			})
+			idx = len(aggs) - 1
+		}
+
+		savings := 0.0
+		for _, f := range info.Flows {
+			if f.TopologyAware {
+				savings += f.CostUSD * topologyReduction
+			}
+		}
+
+		agg := &aggs[idx]
+		agg.Network = info
+		if agg.Metrics == nil {
+			agg.Metrics = map[string]types.MetricStat{}
+		}
+		agg.Metrics["network_cost_usd"] = types.MetricStat{Avg: info.CostUSD}
+		agg.CostCurrentUSD += info.CostUSD
+		agg.CostOptimalUSD += info.CostUSD - savings
+		agg.CostSavingsUSD += savings
+	}
+
+	return aggs
+}
+
+// GenerateTopologyFixActions enables topology-aware routing once on each
+// Service that receives chatty cross-zone flows, with the savings of every
+// flow into it.
+func GenerateTopologyFixActions(aggs []types.AggregatedMetrics) []types.FixAction {
+	out := []types.FixAction{}
+
+	type inbound struct {
+		sources []string
+		gb      float64
+		cost    float64
+	}
+	destinations := []string{}
+	flows := map[string]*inbound{}
+	for _, agg := range aggs {
+		if agg.Network == nil {
+			continue
+		}
+		for _, f := range agg.Network.Flows {
+			if !f.TopologyAware {
+				continue
+			}
+			in, ok := flows[f.Destination]
+			if !ok {
+				in = &inbound{}
+				flows[f.Destination] = in
+				destinations = append(destinations, f.Destination)
+			}
+			if !containsString(in.sources, agg.Resource) {
+				in.sources = append(in.sources, agg.Resource)
+			}
+			in.gb += f.MonthlyGB
+			in.cost += f.CostUSD
+		}
+	}
+	sort.Strings(destinations)
+
+	for _, dest := range destinations {
+		in := flows[dest]
+		sort.Strings(in.sources)
+		sources := strings.Join(in.sources, ", ")
+
+		out = append(out, types.FixAction{
+			Provider: types.ProviderKubernetes,
+			Resource: dest,
+			Intent:   "enable_topology_aware_routing",
+			Description: fmt.Sprintf(
+				"%s → %s sends %.0fGB/month across zones ($%.2f). Route it in-zone with %s: Auto.",
+				sources, dest, in.gb, in.cost, TopologyAnnotation,
+			),
+			// Traffic volumes are estimates that move between runs, so the
+			// operation only names the annotation to keep its ID stable.
+			Action: types.FixOperation{
+				Field:     "metadata.annotations",
+				Operation: "add_annotation",
+				Target:    TopologyAnnotation + "=Auto",
+			},
+			AIGuidance: fmt.Sprintf(
+				"Update the Kubernetes Service '%s'. Add the annotation %s: Auto so kube-proxy prefers endpoints in the caller's zone. Make sure '%s' and its callers (%s) run replicas in every zone; otherwise add a preferred podAffinity on topology.kubernetes.io/zone to co-locate them.",
+				dest, TopologyAnnotation, dest, sources,
+			),
+			EstimatedSavingsUSD: in.cost * topologyReduction,
+			RiskLevel:           "low",
+		})
+	}
+
+	return out
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,224 @@
+package objectstorage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/pricing"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+const (
+	ClassStandard  = "STANDARD"
+	ClassIA        = "STANDARD_IA"
+	ClassGlacierIR = "GLACIER_IR"
+
+	RuleTransitionIA      = "costguard-transition-standard-ia"
+	RuleTransitionGlacier = "costguard-transition-glacier-ir"
+	RuleAbortMultipart    = "costguard-abort-incomplete-multipart"
+
+	iaAfterDays        = 30
+	glacierAfterDays   = 90
+	abortMultipartDays = 7
+
+	// Transitions smaller than this are not worth a lifecycle rule.
+	minTransitionGB = 1.0
+)
+
+// Aggregate prices each bucket from its latest size snapshot and the request
+// rate over the window, and recommends lifecycle rules for it. Sizes come
+// from the most recent sample; requests are summed across samples.
+func Aggregate(provider types.Provider, resources map[string][]types.MetricCollection) []types.AggregatedMetrics {
+	out := []types.AggregatedMetrics{}
+	p := pricing.Default.AWS.S3
+
+	for name, pts := range resources {
+		sort.Slice(pts, func(i, j int) bool {
+			return pts[i].TimeStamp < pts[j].TimeStamp
+		})
+
+		latest := pts[len(pts)-1].Metrics.ObjectStorageResourceMetrics
+
+		gets, puts, downloaded := 0.0, 0.0, 0.0
+		for _, pt := range pts[1:] {
+			m := pt.Metrics.ObjectStorageResourceMetrics
+			gets += m.GetRequests
+			puts += m.PutRequests
+			downloaded += m.DownloadedGB
+		}
+		monthly := 0.0
+		if span := float64(pts[len(pts)-1].TimeStamp - pts[0].TimeStamp); span > 0 {
+			monthly = pricing.SecondsPerMonth / span
+		}
+
+		bucket := &types.BucketInfo{
+			StorageGB:             latest.StorageGB,
+			IncompleteMultipartGB: latest.IncompleteMultipartGB,
+			MonthlyGetRequests:    gets * monthly,
+			MonthlyDownloadedGB:   downloaded * monthly,
+		}
+
+		totalGB := 0.0
+		cost := latest.IncompleteMultipartGB * p.Classes[ClassStandard].PerGBMonth
+		for class, gb := range latest.StorageGB {
+			totalGB += gb
+			cost += gb * p.Classes[class].PerGBMonth
+		}
+		cost += (gets*monthly*p.GetPerThousand + puts*monthly*p.PutPerThousand) / 1000
+
+		bucket.RecommendedRules = RecommendRules(bucket, latest.AgeHistogram)
+
+		savings := 0.0
+		for _, r := range bucket.RecommendedRules {
+			savings += r.SavingsUSD
+		}
+
+		out = append(out, types.AggregatedMetrics{
+			Provider: provider,
+			Resource: name,
+			Metrics: map[string]types.MetricStat{
+				"size_gb":              {Avg: totalGB},
+				"monthly_get_requests": {Avg: bucket.MonthlyGetRequests},
+			},
+			Bucket:         bucket,
+			CostCurrentUSD: cost,
+			CostOptimalUSD: cost - savings,
+			CostSavingsUSD: savings,
+			DataPoints:     len(pts),
+		})
+	}
+
+	return out
+}
+
+// RecommendRules proposes a Standard-IA transition at 30 days, a Glacier
+// Instant Retrieval transition at 90 days and aborting stale multipart
+// uploads. Downloads are assumed to hit every age equally, which
+// overestimates retrieval fees for the usual case of hot recent objects.
+func RecommendRules(bucket *types.BucketInfo, hist []types.ObjectAgeBucket) []types.LifecycleRule {
+	rules := []types.LifecycleRule{}
+	classes := pricing.Default.AWS.S3.Classes
+
+	totalGB := 0.0
+	for _, gb := range bucket.StorageGB {
+		totalGB += gb
+	}
+	standardGB := bucket.StorageGB[ClassStandard]
+
+	// Savings from moving gb of Standard data to class, net of retrieval.
+	savings := func(class string, gb float64) float64 {
+		if totalGB == 0 {
+			return 0
+		}
+		c := classes[class]
+		readGB := bucket.MonthlyDownloadedGB * gb / totalGB
+		return gb*(classes[ClassStandard].PerGBMonth-c.PerGBMonth) - readGB*c.RetrievalPerGB
+	}
+
+	glacierGB := min(OlderThan(hist, glacierAfterDays), standardGB)
+	glacierSavings := savings(ClassGlacierIR, glacierGB)
+	withGlacier := glacierGB >= minTransitionGB && glacierSavings > 0
+
+	iaGB := min(OlderThan(hist, iaAfterDays), standardGB)
+	if withGlacier {
+		iaGB -= glacierGB
+	}
+	if iaSavings := savings(ClassIA, iaGB); iaGB >= minTransitionGB && iaSavings > 0 {
+		rules = append(rules, types.LifecycleRule{
+			ID:           RuleTransitionIA,
+			Days:         iaAfterDays,
+			StorageClass: ClassIA,
+			SizeGB:       iaGB,
+			SavingsUSD:   iaSavings,
+		})
+	}
+
+	if withGlacier {
+		rules = append(rules, types.LifecycleRule{
+			ID:           RuleTransitionGlacier,
+			Days:         glacierAfterDays,
+			StorageClass: ClassGlacierIR,
+			SizeGB:       glacierGB,
+			SavingsUSD:   glacierSavings,
+		})
+	}
+
+	if bucket.IncompleteMultipartGB > 0 {
+		rules = append(rules, types.LifecycleRule{
+			ID:         RuleAbortMultipart,
+			Days:       abortMultipartDays,
+			SizeGB:     bucket.IncompleteMultipartGB,
+			SavingsUSD: bucket.IncompleteMultipartGB * classes[ClassStandard].PerGBMonth,
+		})
+	}
+
+	return rules
+}
+
+// OlderThan is the size of objects at least days old. Buckets straddling
+// days are prorated linearly. An open-ended bucket that starts before days
+// is left out, since nothing says how much of it is old enough, so savings
+// are never overstated.
+func OlderThan(hist []types.ObjectAgeBucket, days int) float64 {
+	total := 0.0
+	for _, b := range hist {
+		switch {
+		case b.MinDays >= days:
+			total += b.SizeGB
+		case b.MaxDays == 0:
+			continue
+		case b.MaxDays > days:
+			total += b.SizeGB * float64(b.MaxDays-days) / float64(b.MaxDays-b.MinDays)
+		}
+	}
+	return total
+}
+
+// GenerateLifecycleFixActions turns each recommended rule into an action
+// that adds it to the bucket's lifecycle configuration.
+func GenerateLifecycleFixActions(agg types.AggregatedMetrics) []types.FixAction {
+	out := []types.FixAction{}
+	if agg.Bucket == nil {
+		return out
+	}
+
+	for _, r := range agg.Bucket.RecommendedRules {
+		action := types.FixAction{
+			Provider: agg.Provider,
+			Resource: agg.Resource,
+			Action: types.FixOperation{
+				Operation: "add_lifecycle_rule",
+				Value:     float64(r.Days),
+				Unit:      "days",
+				Target:    r.ID,
+			},
+			EstimatedSavingsUSD: r.SavingsUSD,
+		}
+
+		if r.StorageClass == "" {
+			action.Intent = "abort_incomplete_multipart_uploads"
+			action.Description = fmt.Sprintf("Abort incomplete multipart uploads after %d days (%.1fGB pending)", r.Days, r.SizeGB)
+			action.Action.Field = "lifecycle.abort_incomplete_multipart_upload"
+			action.RiskLevel = "low"
+		} else {
+			action.Intent = "transition_" + strings.ToLower(r.StorageClass)
+			action.Description = fmt.Sprintf("Transition objects older than %d days to %s (%.1fGB, minimum storage %d days)",
+				r.Days, r.StorageClass, r.SizeGB, pricing.Default.AWS.S3.Classes[r.StorageClass].MinDays)
+			action.Action.Field = "lifecycle.transition." + r.StorageClass
+			action.RiskLevel = "low"
+			if r.StorageClass == ClassGlacierIR {
+				action.RiskLevel = "medium"
+			}
+		}
+
+		action.AIGuidance = fmt.Sprintf(
+			"Add lifecycle rule '%s' to bucket '%s' (Terraform aws_s3_bucket_lifecycle_configuration or a JSON lifecycle document): %s.",
+			r.ID, agg.Resource, action.Description,
+		)
+
+		out = append(out, action)
+	}
+
+	return out
+}
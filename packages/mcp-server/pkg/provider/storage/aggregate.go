@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"math"
+	"sort"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/pricing"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/utils"
+)
+
+const (
+	capacityHeadroom = 1.3
+	minCapacityGB    = 1
+)
+
+func Aggregate(resources map[string][]types.MetricCollection) []types.AggregatedMetrics {
+	out := []types.AggregatedMetrics{}
+
+	for name, pts := range resources {
+		sort.Slice(pts, func(i, j int) bool {
+			return pts[i].TimeStamp < pts[j].TimeStamp
+		})
+
+		usedVals := make([]float64, 0, len(pts))
+		capVals := make([]float64, 0, len(pts))
+		// A claim is only orphaned on an explicit signal: the collector
+		// says so, or reported mounts on every sample and none had a pod.
+		// Collectors that don't report mounts never orphan a claim.
+		mounted, mountsReported := false, true
+
+		for _, p := range pts {
+			m := p.Metrics.PVCResourceMetrics
+			usedVals = append(usedVals, m.UsedGB)
+			capVals = append(capVals, m.CapacityGB)
+			switch {
+			case m.MountedBy == nil:
+				mountsReported = false
+			case *m.MountedBy != "":
+				mounted = true
+			}
+		}
+
+		// Class, phase and ownership are taken from the most recent sample.
+		latest := pts[len(pts)-1].Metrics.PVCResourceMetrics
+		usedStat := utils.ComputeStat(usedVals)
+
+		info := &types.StorageInfo{
+			StorageClass:          latest.StorageClass,
+			RecommendedClass:      latest.StorageClass,
+			CapacityGB:            latest.CapacityGB,
+			RecommendedCapacityGB: RecommendedCapacity(usedStat, latest.CapacityGB),
+			Phase:                 latest.Phase,
+			OwnerKind:             latest.OwnerKind,
+			Owner:                 latest.Owner,
+			Unbound:               latest.Phase != "" && latest.Phase != "Bound",
+			Orphaned:              !mounted && (latest.Orphaned || mountsReported),
+		}
+
+		if class, ok := pricing.Default.Storage[latest.StorageClass]; ok && class.Downgrade != "" {
+			info.RecommendedClass = class.Downgrade
+		}
+
+		currentCost := ComputeCost(info.StorageClass, info.CapacityGB)
+		optimalCost := ComputeCost(info.RecommendedClass, info.RecommendedCapacityGB)
+		if info.Unbound || info.Orphaned {
+			optimalCost = 0
+		}
+
+		out = append(out, types.AggregatedMetrics{
+			Provider: types.ProviderKubernetesStorage,
+			Resource: name,
+			Metrics: map[string]types.MetricStat{
+				"used_gb":     usedStat,
+				"capacity_gb": utils.ComputeStat(capVals),
+			},
+			Storage:        info,
+			CostCurrentUSD: currentCost,
+			CostOptimalUSD: optimalCost,
+			CostSavingsUSD: currentCost - optimalCost,
+			DataPoints:     len(pts),
+		})
+	}
+
+	return out
+}
+
+// RecommendedCapacity sizes a volume to its P95 usage plus headroom, rounded
+// up to whole GB. It never recommends growing a volume.
+func RecommendedCapacity(used types.MetricStat, capacityGB float64) float64 {
+	target := math.Max(math.Ceil(used.P95*capacityHeadroom), minCapacityGB)
+	return math.Min(target, capacityGB)
+}
+
+func ComputeCost(storageClass string, capacityGB float64) float64 {
+	return capacityGB * pricing.Default.Storage[storageClass].PerGBMonth
+}
@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+const minShrinkPercent = 20
+
+// GenerateStorageFixActions deletes unbound or orphaned volumes, and
+// otherwise shrinks oversized volumes and moves them to cheaper storage
+// classes. Volumes owned by a StatefulSet are left to
+// GenerateClaimTemplateFixActions. Neither PVC capacity nor class can be
+// changed in place, so every action needs approval and a data migration.
+func GenerateStorageFixActions(agg types.AggregatedMetrics) []types.FixAction {
+	out := []types.FixAction{}
+
+	info := agg.Storage
+	if info == nil {
+		return out
+	}
+
+	if info.Unbound || info.Orphaned {
+		reason := "is not mounted by any pod"
+		if info.Unbound {
+			reason = fmt.Sprintf("is %s, not Bound", info.Phase)
+		}
+
+		return append(out, types.FixAction{
+			Provider:    types.ProviderKubernetesStorage,
+			Resource:    agg.Resource,
+			Intent:      "delete",
+			Description: fmt.Sprintf("Delete volume %s: it %s (%.0fGB %s)", agg.Resource, reason, info.CapacityGB, info.StorageClass),
+			Action: types.FixOperation{
+				Operation: "delete",
+				Current:   info.CapacityGB,
+				Unit:      "GB",
+			},
+			AIGuidance: fmt.Sprintf(
+				"Confirm the data on PVC '%s' is no longer needed (snapshot it if unsure), then delete the PVC and remove its manifest.",
+				agg.Resource,
+			),
+			EstimatedSavingsUSD: agg.CostCurrentUSD,
+			RiskLevel:           "high",
+			RequiresApproval:    true,
+		})
+	}
+
+	if ownedByStatefulSet(info) {
+		return out
+	}
+
+	return resizeActions(agg.Resource, "volume "+agg.Resource, "spec.",
+		info.StorageClass, info.RecommendedClass, []float64{info.CapacityGB},
+		info.CapacityGB, info.RecommendedCapacityGB, agg.Metrics["used_gb"].P95)
+}
+
+// GenerateClaimTemplateFixActions changes the volumeClaimTemplates of each
+// StatefulSet once for all of its claims. The template is sized for the
+// largest claim so no replica runs out of space.
+func GenerateClaimTemplateFixActions(aggs []types.AggregatedMetrics) []types.FixAction {
+	out := []types.FixAction{}
+
+	owners := []string{}
+	claims := map[string][]types.AggregatedMetrics{}
+	for _, agg := range aggs {
+		info := agg.Storage
+		if agg.Provider != types.ProviderKubernetesStorage || info == nil ||
+			info.Unbound || info.Orphaned || !ownedByStatefulSet(info) {
+			continue
+		}
+		if claims[info.Owner] == nil {
+			owners = append(owners, info.Owner)
+		}
+		claims[info.Owner] = append(claims[info.Owner], agg)
+	}
+	sort.Strings(owners)
+
+	for _, owner := range owners {
+		first := claims[owner][0].Storage
+		capacities := []float64{}
+		capacity, recommended, usedP95 := 0.0, 0.0, 0.0
+		for _, c := range claims[owner] {
+			capacities = append(capacities, c.Storage.CapacityGB)
+			capacity = math.Max(capacity, c.Storage.CapacityGB)
+			recommended = math.Max(recommended, c.Storage.RecommendedCapacityGB)
+			usedP95 = math.Max(usedP95, c.Metrics["used_gb"].P95)
+		}
+
+		label := fmt.Sprintf("%d volumes of %s", len(capacities), owner)
+		out = append(out, resizeActions(owner, label, "spec.volumeClaimTemplates.spec.",
+			first.StorageClass, first.RecommendedClass, capacities,
+			capacity, recommended, usedP95)...)
+	}
+
+	return out
+}
+
+func ownedByStatefulSet(info *types.StorageInfo) bool {
+	return info.OwnerKind == "StatefulSet" && info.Owner != ""
+}
+
+// resizeActions shrinks and reclasses the claim (or claim template) target.
+// Savings are summed over capacities, the current size of every volume the
+// change applies to.
+// A class change is priced at the recommended size only when the shrink is
+// part of the plan, and at the current size otherwise.
+func resizeActions(
+	target, label, fieldPrefix string,
+	class, recommendedClass string,
+	capacities []float64,
+	capacity, recommended, usedP95 float64,
+) []types.FixAction {
+	out := []types.FixAction{}
+
+	shrinkPercent := (1 - recommended/capacity) * 100
+	shrink := capacity > 0 && shrinkPercent >= minShrinkPercent
+	if shrink {
+		savings := 0.0
+		for _, c := range capacities {
+			savings += ComputeCost(class, c) - ComputeCost(class, math.Min(c, recommended))
+		}
+		out = append(out, types.FixAction{
+			Provider: types.ProviderKubernetesStorage,
+			Resource: target,
+			Intent:   "shrink_volume",
+			Description: fmt.Sprintf(
+				"Shrink %s %.0fGB → %.0fGB (P95 used %.1fGB)",
+				label, capacity, recommended, usedP95,
+			),
+			Action: types.FixOperation{
+				Field:     fieldPrefix + "resources.requests.storage",
+				Operation: "set_to",
+				Current:   capacity,
+				Value:     recommended,
+				Unit:      "GB",
+			},
+			AIGuidance: fmt.Sprintf(
+				"Set the storage request for '%s' to %.0fGi. PVCs cannot shrink in place: create a new volume, copy the data and switch the workload over.",
+				target, recommended,
+			),
+			EstimatedSavingsUSD: savings,
+			RiskLevel:           "high",
+			RequiresApproval:    true,
+		})
+	}
+
+	if recommendedClass != class {
+		savings := 0.0
+		for _, c := range capacities {
+			size := c
+			if shrink {
+				size = math.Min(c, recommended)
+			}
+			savings += ComputeCost(class, size) - ComputeCost(recommendedClass, size)
+		}
+		out = append(out, types.FixAction{
+			Provider: types.ProviderKubernetesStorage,
+			Resource: target,
+			Intent:   "change_storage_class",
+			Description: fmt.Sprintf(
+				"Move %s from storage class %s → %s",
+				label, class, recommendedClass,
+			),
+			Action: types.FixOperation{
+				Field:     fieldPrefix + "storageClassName",
+				Operation: "set_to",
+				Target:    recommendedClass,
+			},
+			AIGuidance: fmt.Sprintf(
+				"Set storageClassName for '%s' to %s and migrate the existing data to a volume of the new class.",
+				target, recommendedClass,
+			),
+			EstimatedSavingsUSD: savings,
+			RiskLevel:           "high",
+			RequiresApproval:    true,
+		})
+	}
+
+	return out
+}
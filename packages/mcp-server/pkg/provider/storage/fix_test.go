@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"math"
+	"testing"
+)
+
+func TestResizeActions(t *testing.T) {
+	tests := []struct {
+		name          string
+		capacities    []float64
+		capacity      float64
+		recommended   float64
+		class         string
+		recommendedTo string
+		shrink        float64 // expected shrink_volume savings, 0 when not emitted
+		classChange   float64 // expected change_storage_class savings, 0 when not emitted
+	}{
+		{
+			// 100GB → 40GB on gp2, then gp2 → gp3 on the 40GB left.
+			name:          "shrink and reclass",
+			capacities:    []float64{100},
+			capacity:      100,
+			recommended:   40,
+			class:         "gp2",
+			recommendedTo: "gp3",
+			shrink:        60 * 0.10,
+			classChange:   40 * (0.10 - 0.08),
+		},
+		{
+			// A 10% shrink is below minShrinkPercent, so the class change
+			// applies to the full volume.
+			name:          "reclass without shrink",
+			capacities:    []float64{100},
+			capacity:      100,
+			recommended:   90,
+			class:         "gp2",
+			recommendedTo: "gp3",
+			classChange:   100 * (0.10 - 0.08),
+		},
+		{
+			name:        "shrink only",
+			capacities:  []float64{100, 50},
+			capacity:    100,
+			recommended: 50,
+			class:       "gp3",
+			shrink:      50 * 0.08,
+		},
+		{
+			name:          "claim template volumes of different sizes",
+			capacities:    []float64{100, 80},
+			capacity:      100,
+			recommended:   95,
+			class:         "io1",
+			recommendedTo: "gp3",
+			classChange:   180 * (0.125 - 0.08),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			to := tt.recommendedTo
+			if to == "" {
+				to = tt.class
+			}
+			actions := resizeActions("data", "PVC data", "spec.", tt.class, to, tt.capacities, tt.capacity, tt.recommended, tt.recommended*0.5)
+
+			got := map[string]float64{}
+			for _, a := range actions {
+				got[a.Intent] = a.EstimatedSavingsUSD
+			}
+			for intent, want := range map[string]float64{"shrink_volume": tt.shrink, "change_storage_class": tt.classChange} {
+				savings, ok := got[intent]
+				if ok != (want > 0) {
+					t.Errorf("%s emitted = %v, want %v", intent, ok, want > 0)
+					continue
+				}
+				if math.Abs(savings-want) > 1e-9 {
+					t.Errorf("%s savings = %.4f, want %.4f", intent, savings, want)
+				}
+			}
+		})
+	}
+}
@@ -1,13 +1,23 @@
 package scan
 
 import (
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/anomaly"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/provider/aws"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/provider/azure"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/provider/gcp"
 	"github.com/tanay13/costguard/packages/mcp-server/pkg/provider/kubernetes"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/provider/network"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/provider/objectstorage"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/provider/storage"
 	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
 )
 
 func DataPointAggregator(
 	points []types.MetricCollection,
 	actualRequests map[string]types.Requests,
+	autoscalers map[string]types.HPAConfig,
+	workloads map[string]types.WorkloadInfo,
+	rec kubernetes.Recommender,
 ) []types.AggregatedMetrics {
 
 	grouped := make(map[types.Provider]map[string][]types.MetricCollection)
@@ -22,10 +32,70 @@ func DataPointAggregator(
 	out := []types.AggregatedMetrics{}
 
 	if km, ok := grouped[types.ProviderKubernetes]; ok {
-		out = append(out, kubernetes.Aggregate(km, actualRequests)...)
+		out = append(out, kubernetes.Aggregate(km, actualRequests, autoscalers, workloads, rec)...)
 	}
 
+	if sm, ok := grouped[types.ProviderKubernetesStorage]; ok {
+		out = append(out, storage.Aggregate(sm)...)
+	}
+
+	if cr, ok := grouped[types.ProviderGCPCloudRun]; ok {
+		out = append(out, gcp.AggregateCloudRun(cr)...)
+	}
+
+	if gce, ok := grouped[types.ProviderGCPGCE]; ok {
+		out = append(out, gcp.AggregateGCE(gce)...)
+	}
+
+	if ca, ok := grouped[types.ProviderAzureContainerApps]; ok {
+		out = append(out, azure.AggregateContainerApps(ca)...)
+	}
+
+	if vm, ok := grouped[types.ProviderAzureVM]; ok {
+		out = append(out, azure.AggregateVMs(vm)...)
+	}
 
+	if ecs, ok := grouped[types.ProviderAWSECSFargate]; ok {
+		out = append(out, aws.AggregateFargate(ecs, rec)...)
+	}
+
+	if rds, ok := grouped[types.ProviderAWSRDS]; ok {
+		out = append(out, aws.AggregateRDS(rds)...)
+	}
+
+	for _, provider := range []types.Provider{types.ProviderAWSS3, types.ProviderObjectStorage} {
+		if buckets, ok := grouped[provider]; ok {
+			out = append(out, objectstorage.Aggregate(provider, buckets)...)
+		}
+	}
+
+	if flows, ok := grouped[types.ProviderNetworkFlow]; ok {
+		out = network.Attribute(out, network.AggregateFlows(flows))
+	}
+
+	for i := range out {
+		samples := grouped[out[i].Provider][out[i].Resource]
+		out[i].Labels = mergeLabels(samples)
+		out[i].Anomalies = anomaly.Detect(out[i].Provider, samples)
+	}
 
 	return out
 }
+
+// mergeLabels combines the labels of all samples of a resource. When a label
+// changed over the window, the most recent value wins.
+func mergeLabels(samples []types.MetricCollection) map[string]string {
+	var labels map[string]string
+	var seen map[string]int64
+	for _, s := range samples {
+		for k, v := range s.Labels {
+			if labels == nil {
+				labels, seen = map[string]string{}, map[string]int64{}
+			}
+			if ts, ok := seen[k]; !ok || s.TimeStamp >= ts {
+				labels[k], seen[k] = v, s.TimeStamp
+			}
+		}
+	}
+	return labels
+}
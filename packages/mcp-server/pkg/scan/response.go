@@ -21,17 +21,38 @@ func BuildScanResponse(agg []types.AggregatedMetrics) types.ScanResponse {
 		res := types.ScanResource{
 			Provider: a.Provider,
 			Resource: a.Resource,
+			Labels:   a.Labels,
 			Usage:    a.Metrics,
 			Costs: types.ScanResourceCost{
 				CurrentCostUSD:      a.CostCurrentUSD,
 				OptimalCostUSD:      a.CostOptimalUSD,
 				PotentialSavingsUSD: a.CostSavingsUSD,
-				WastePercentage:     (a.CostSavingsUSD / a.CostCurrentUSD) * 100,
 			},
 		}
 
+		if a.CostCurrentUSD > 0 {
+			res.Costs.WastePercentage = (a.CostSavingsUSD / a.CostCurrentUSD) * 100
+		}
+
 		res.Requested.CpuMilli = a.RequestedCpuMilli
 		res.Requested.MemoryGB = a.RequestedMemoryGB
+		res.Recommended = types.Requests{
+			CpuMilli: a.OptimalCpuMilli,
+			MemoryGB: a.OptimalMemoryGB,
+		}
+		res.Recommender = a.Recommender
+		res.Bounds = a.Bounds
+		res.Autoscaler = a.Autoscaler
+		res.Idle = a.Idle
+		res.Workload = a.Workload
+		res.Storage = a.Storage
+		res.Serverless = a.Serverless
+		res.Machine = a.Machine
+		res.Database = a.Database
+		res.Bucket = a.Bucket
+		res.Network = a.Network
+		res.Anomalies = a.Anomalies
+		res.Overhead = a.Overhead
 
 		totalCurrent += a.CostCurrentUSD
 		totalOptimal += a.CostOptimalUSD
@@ -52,9 +73,11 @@ func BuildScanResponse(agg []types.AggregatedMetrics) types.ScanResponse {
 	})
 
 	top := []string{}
-	for i := 0; i < len(temp) && i < 3; i++ {
-		top = append(top, temp[i].name)
-		resp.Resources = append(resp.Resources, temp[i].data)
+	for i, t := range temp {
+		if i < 3 {
+			top = append(top, t.name)
+		}
+		resp.Resources = append(resp.Resources, t.data)
 	}
 
 	resp.Summary = types.ScanSummary{
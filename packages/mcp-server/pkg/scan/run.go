@@ -1,9 +1,71 @@
 package scan
 
-import "github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+import (
+	"fmt"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/events"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/provider/kubernetes"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
 
 func RunScan(req types.ScanRequest) (types.ScanResponse, error) {
-	agg := DataPointAggregator(req.Metrics, req.ActualRequests)
+	resp, err := runScan(req)
+	if err != nil {
+		events.EmitError("scan", "", err)
+		return resp, err
+	}
+
+	events.Emit(events.Event{
+		Type:   events.ScanExecuted,
+		Source: "scan",
+		Message: fmt.Sprintf("%d resources, $%.2f / month, $%.2f potential savings",
+			len(resp.Resources), resp.Summary.TotalCurrentCostUSD, resp.Summary.TotalPotentialSavingsUSD),
+		Payload: resp.Summary,
+	})
+	emitAnomalies(resp)
+	return resp, nil
+}
+
+func runScan(req types.ScanRequest) (types.ScanResponse, error) {
+	rec, err := kubernetes.NewRecommender(req.Recommender)
+	if err != nil {
+		return types.ScanResponse{}, err
+	}
+
+	agg := DataPointAggregator(req.Metrics, req.ActualRequests, req.Autoscalers, req.Workloads, rec)
+
+	var cluster *types.ClusterSummary
+	if req.Cluster != nil {
+		s, err := kubernetes.DistributeOverhead(agg, *req.Cluster)
+		if err != nil {
+			return types.ScanResponse{}, err
+		}
+		cluster = &s
+	}
+
 	resp := BuildScanResponse(agg)
+
+	// Idle capacity is paid for but not requested by anyone, so it only
+	// shows up in the totals once node cost is known. It is added to both
+	// sides: rightsizing alone does not shrink the node bill.
+	if cluster != nil {
+		resp.Summary.Cluster = cluster
+		resp.Summary.TotalCurrentCostUSD += cluster.IdleCostUSD
+		resp.Summary.TotalOptimalCostUSD += cluster.IdleCostUSD
+	}
 	return resp, nil
 }
+
+// emitAnomalies records each detected anomaly in the event log.
+func emitAnomalies(resp types.ScanResponse) {
+	for _, r := range resp.Resources {
+		for _, a := range r.Anomalies {
+			events.Emit(events.Event{
+				Type:     events.AnomalyDetected,
+				Source:   "scan",
+				Resource: r.Resource,
+				Payload:  a,
+			})
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package simulate
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/provider/kubernetes"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+type node struct {
+	pool    types.NodePool
+	freeCPU float64
+	freeMem float64
+	pods    int
+}
+
+type pod struct {
+	resource string
+	req      types.Requests
+}
+
+// RunSimulation packs the current and the recommended pod requests onto the
+// node inventory with first-fit-decreasing and compares how many nodes each
+// packing needs. Pods come from req.Pods, or from req.Scan when none are
+// given.
+func RunSimulation(req types.SimulationRequest) (types.SimulationResponse, error) {
+	placements := req.Pods
+	if len(placements) == 0 && req.Scan != nil {
+		placements = PlacementsFromScan(*req.Scan)
+	}
+	if len(placements) == 0 {
+		return types.SimulationResponse{}, fmt.Errorf("no pods to place")
+	}
+
+	fleetNodes := 0
+	fleetCost := 0.0
+	for _, n := range req.Nodes {
+		fleetNodes += n.Count
+		fleetCost += float64(n.Count) * n.MonthlyPriceUSD
+	}
+	if fleetNodes == 0 {
+		return types.SimulationResponse{}, fmt.Errorf("node inventory is empty")
+	}
+
+	current := binPack(req.Nodes, expand(placements, func(p types.PodPlacement) types.Requests { return p.Current }))
+	recommended := binPack(req.Nodes, expand(placements, func(p types.PodPlacement) types.Requests { return p.Recommended }))
+
+	perPod := 0.0
+	for _, p := range placements {
+		perPod += float64(max(p.Replicas, 1)) * (kubernetes.ComputeCostFromRequests(p.Current.CpuMilli, p.Current.MemoryGB) -
+			kubernetes.ComputeCostFromRequests(p.Recommended.CpuMilli, p.Recommended.MemoryGB))
+	}
+
+	drained := current.NodesUsed - recommended.NodesUsed
+	delta := current.CostUSD - recommended.CostUSD
+
+	summary := fmt.Sprintf(
+		"Nodes: %d → %d (%d drainable), node cost: $%.2f → $%.2f (savings: $%.2f, per-pod estimate: $%.2f)",
+		current.NodesUsed, recommended.NodesUsed, drained,
+		current.CostUSD, recommended.CostUSD, delta, perPod,
+	)
+
+	return types.SimulationResponse{
+		FleetNodes:       fleetNodes,
+		FleetCostUSD:     fleetCost,
+		Current:          current,
+		Recommended:      recommended,
+		NodesDrained:     drained,
+		CostDeltaUSD:     delta,
+		PerPodSavingsUSD: perPod,
+		Summary:          summary,
+	}, nil
+}
+
+// PlacementsFromScan turns scanned Kubernetes resources into pods, using the
+// P95 replica count when the scan observed one.
+func PlacementsFromScan(scan types.ScanResponse) []types.PodPlacement {
+	out := []types.PodPlacement{}
+
+	for _, r := range scan.Resources {
+		if r.Provider != types.ProviderKubernetes {
+			continue
+		}
+
+		replicas := 1
+		if stat, ok := r.Usage["replicas"]; ok && stat.P95 > 0 {
+			replicas = int(math.Round(stat.P95))
+		}
+
+		out = append(out, types.PodPlacement{
+			Resource: r.Resource,
+			Replicas: replicas,
+			Current: types.Requests{
+				CpuMilli: r.Requested.CpuMilli,
+				MemoryGB: r.Requested.MemoryGB,
+			},
+			Recommended: r.Recommended,
+		})
+	}
+
+	return out
+}
+
+func expand(placements []types.PodPlacement, pick func(types.PodPlacement) types.Requests) []pod {
+	out := []pod{}
+	for _, p := range placements {
+		replicas := max(p.Replicas, 1)
+		for i := 0; i < replicas; i++ {
+			out = append(out, pod{resource: p.Resource, req: pick(p)})
+		}
+	}
+	return out
+}
+
+// binPack places pods largest-first onto the first node with room. Nodes are
+// tried biggest first (cheapest first among equals), so small leftovers end
+// up on nodes that can be drained.
+func binPack(pools []types.NodePool, pods []pod) types.SimulationResult {
+	nodes := []*node{}
+	for _, pool := range pools {
+		for i := 0; i < pool.Count; i++ {
+			nodes = append(nodes, &node{pool: pool, freeCPU: pool.AllocatableCpuMilli, freeMem: pool.AllocatableMemoryGB})
+		}
+	}
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, b := nodes[i].pool, nodes[j].pool
+		if a.AllocatableCpuMilli != b.AllocatableCpuMilli {
+			return a.AllocatableCpuMilli > b.AllocatableCpuMilli
+		}
+		if a.AllocatableMemoryGB != b.AllocatableMemoryGB {
+			return a.AllocatableMemoryGB > b.AllocatableMemoryGB
+		}
+		return a.MonthlyPriceUSD < b.MonthlyPriceUSD
+	})
+
+	// Order pods by their dominant share of the largest node so CPU-heavy
+	// and memory-heavy pods are compared on the same scale.
+	maxCPU, maxMem := 0.0, 0.0
+	for _, n := range nodes {
+		maxCPU = math.Max(maxCPU, n.pool.AllocatableCpuMilli)
+		maxMem = math.Max(maxMem, n.pool.AllocatableMemoryGB)
+	}
+	share := func(p pod) float64 {
+		s := 0.0
+		if maxCPU > 0 {
+			s = p.req.CpuMilli / maxCPU
+		}
+		if maxMem > 0 {
+			s = math.Max(s, p.req.MemoryGB/maxMem)
+		}
+		return s
+	}
+	sort.SliceStable(pods, func(i, j int) bool {
+		return share(pods[i]) > share(pods[j])
+	})
+
+	result := types.SimulationResult{NodesByType: map[string]int{}}
+
+	for _, p := range pods {
+		placed := false
+		for _, n := range nodes {
+			if n.freeCPU >= p.req.CpuMilli && n.freeMem >= p.req.MemoryGB {
+				n.freeCPU -= p.req.CpuMilli
+				n.freeMem -= p.req.MemoryGB
+				n.pods++
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			result.Unschedulable = append(result.Unschedulable, p.resource)
+		}
+	}
+
+	for _, n := range nodes {
+		if n.pods == 0 {
+			continue
+		}
+		result.NodesUsed++
+		result.NodesByType[n.pool.InstanceType]++
+		result.CostUSD += n.pool.MonthlyPriceUSD
+	}
+
+	return result
+}
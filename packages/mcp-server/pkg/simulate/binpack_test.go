@@ -0,0 +1,123 @@
+package simulate
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/provider/kubernetes"
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+func cpuPods(millis ...float64) []pod {
+	out := []pod{}
+	for _, m := range millis {
+		out = append(out, pod{resource: "p", req: types.Requests{CpuMilli: m}})
+	}
+	return out
+}
+
+func TestBinPack(t *testing.T) {
+	big := types.NodePool{InstanceType: "big", AllocatableCpuMilli: 4000, AllocatableMemoryGB: 16, MonthlyPriceUSD: 50, Count: 3}
+	small := types.NodePool{InstanceType: "small", AllocatableCpuMilli: 2000, AllocatableMemoryGB: 8, MonthlyPriceUSD: 30, Count: 2}
+
+	tests := []struct {
+		name          string
+		pools         []types.NodePool
+		pods          []pod
+		nodes         int
+		cost          float64
+		unschedulable int
+	}{
+		{
+			// First-fit in the given order needs three nodes; sorting
+			// largest first pairs 3000+1000 and 2000+2000.
+			name:  "first fit decreasing",
+			pools: []types.NodePool{big},
+			pods:  cpuPods(1000, 2000, 3000, 2000),
+			nodes: 2,
+			cost:  100,
+		},
+		{
+			name:  "biggest nodes first",
+			pools: []types.NodePool{small, big},
+			pods:  cpuPods(1000, 1000, 1000, 1000),
+			nodes: 1,
+			cost:  50,
+		},
+		{
+			name:          "unschedulable",
+			pools:         []types.NodePool{small},
+			pods:          cpuPods(5000, 1000),
+			nodes:         1,
+			cost:          30,
+			unschedulable: 1,
+		},
+		{
+			// Pools without memory figures must not turn the dominant
+			// share into NaN and scramble the ordering.
+			name:  "zero memory capacity",
+			pools: []types.NodePool{{InstanceType: "cpu-only", AllocatableCpuMilli: 4000, MonthlyPriceUSD: 50, Count: 3}},
+			pods:  cpuPods(1000, 2000, 3000, 2000),
+			nodes: 2,
+			cost:  100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := binPack(tt.pools, tt.pods)
+			if got.NodesUsed != tt.nodes {
+				t.Errorf("NodesUsed = %d, want %d", got.NodesUsed, tt.nodes)
+			}
+			if got.CostUSD != tt.cost {
+				t.Errorf("CostUSD = %.2f, want %.2f", got.CostUSD, tt.cost)
+			}
+			if len(got.Unschedulable) != tt.unschedulable {
+				t.Errorf("Unschedulable = %v, want %d", got.Unschedulable, tt.unschedulable)
+			}
+		})
+	}
+}
+
+func TestRunSimulationFromScan(t *testing.T) {
+	res := types.ScanResource{
+		Provider:    types.ProviderKubernetes,
+		Resource:    "api",
+		Usage:       map[string]types.MetricStat{"replicas": {P95: 3}},
+		Recommended: types.Requests{CpuMilli: 250, MemoryGB: 0.5},
+	}
+	res.Requested.CpuMilli = 1000
+	res.Requested.MemoryGB = 2
+
+	scan := types.ScanResponse{
+		Resources: []types.ScanResource{res},
+		Summary:   types.ScanSummary{TotalPotentialSavingsUSD: 999},
+	}
+
+	resp, err := RunSimulation(types.SimulationRequest{
+		Nodes: []types.NodePool{{InstanceType: "big", AllocatableCpuMilli: 4000, AllocatableMemoryGB: 16, MonthlyPriceUSD: 50, Count: 2}},
+		Scan:  &scan,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := 3 * (kubernetes.ComputeCostFromRequests(1000, 2) - kubernetes.ComputeCostFromRequests(250, 0.5))
+	if math.Abs(resp.PerPodSavingsUSD-want) > 1e-9 {
+		t.Errorf("PerPodSavingsUSD = %.4f, want %.4f from the placements", resp.PerPodSavingsUSD, want)
+	}
+	if resp.Current.NodesUsed != 1 || resp.Recommended.NodesUsed != 1 {
+		t.Errorf("nodes used = %d → %d, want 1 → 1", resp.Current.NodesUsed, resp.Recommended.NodesUsed)
+	}
+}
+
+func TestRunSimulationErrors(t *testing.T) {
+	pods := []types.PodPlacement{{Resource: "api", Replicas: 1, Current: types.Requests{CpuMilli: 100}}}
+
+	if _, err := RunSimulation(types.SimulationRequest{Nodes: []types.NodePool{{Count: 1}}}); err == nil {
+		t.Error("expected an error without pods")
+	}
+	if _, err := RunSimulation(types.SimulationRequest{Pods: pods}); err == nil {
+		t.Error("expected an error without nodes")
+	}
+}
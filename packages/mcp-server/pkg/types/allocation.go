@@ -0,0 +1,41 @@
+package types
+
+const (
+	// Unlabelled names the group of resources missing a group-by label.
+	Unlabelled = "(unlabelled)"
+
+	// UnlabelledKeep reports unlabelled spend as its own group (showback);
+	// UnlabelledDistribute charges it to the labelled groups in proportion
+	// to their cost (chargeback).
+	UnlabelledKeep       = "keep"
+	UnlabelledDistribute = "distribute"
+)
+
+type AllocationGroup struct {
+	Value     string   `json:"value"`
+	Resources []string `json:"resources"`
+
+	CurrentCostUSD float64 `json:"current_cost_usd"`
+	OptimalCostUSD float64 `json:"optimal_cost_usd"`
+	SavingsUSD     float64 `json:"savings_usd"`
+	// OverheadCostUSD is the group's share of shared and idle cluster cost.
+	OverheadCostUSD float64 `json:"overhead_cost_usd,omitempty"`
+	// SharedCostUSD is unlabelled spend charged to this group.
+	SharedCostUSD float64 `json:"shared_cost_usd,omitempty"`
+	TotalCostUSD  float64 `json:"total_cost_usd"`
+	SharePercent  float64 `json:"share_percent"`
+}
+
+type AllocationReport struct {
+	GroupBy           []string          `json:"group_by"`
+	Unlabelled        string            `json:"unlabelled"`
+	TotalCostUSD      float64           `json:"total_cost_usd"`
+	UnlabelledCostUSD float64           `json:"unlabelled_cost_usd"`
+	Groups            []AllocationGroup `json:"groups"`
+}
+
+type ReportRequest struct {
+	Scan       ScanResponse `json:"scan"`
+	GroupBy    string       `json:"group_by"`
+	Unlabelled string       `json:"unlabelled"`
+}
@@ -0,0 +1,26 @@
+package types
+
+const (
+	AnomalySpike       = "spike"
+	AnomalyDrop        = "drop"
+	AnomalyLevelShift  = "level_shift"
+	AnomalyGrowthTrend = "growth_trend"
+
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Anomaly is an abrupt change in one usage metric of a resource. Ongoing
+// anomalies are still visible at the end of the sampled window, so the
+// usage they distort should not drive rightsizing yet.
+type Anomaly struct {
+	Metric      string  `json:"metric"`
+	Kind        string  `json:"kind"`
+	Severity    string  `json:"severity"`
+	StartedAt   int64   `json:"started_at"`
+	Ongoing     bool    `json:"ongoing"`
+	Baseline    float64 `json:"baseline"`
+	Current     float64 `json:"current"`
+	Score       float64 `json:"score"`
+	Description string  `json:"description"`
+}
@@ -0,0 +1,35 @@
+package types
+
+// CheckThresholds configures the CI cost gate. A zero value disables the
+// rule.
+type CheckThresholds struct {
+	BudgetTarget          float64 `json:"budget_target_usd,omitempty"`
+	MaxGrowthPercent      float64 `json:"max_growth_percent,omitempty"`
+	MaxWastePercent       float64 `json:"max_waste_percent,omitempty"`
+	MaxNewResourceCostUSD float64 `json:"max_new_resource_cost_usd,omitempty"`
+}
+
+const (
+	CheckRuleBudget      = "budget"
+	CheckRuleGrowth      = "growth"
+	CheckRuleWaste       = "waste"
+	CheckRuleNewResource = "new_resource"
+)
+
+type CheckViolation struct {
+	Rule     string   `json:"rule"`
+	Provider Provider `json:"provider,omitempty"`
+	Resource string   `json:"resource,omitempty"`
+	Actual   float64  `json:"actual"`
+	Limit    float64  `json:"limit"`
+	Message  string   `json:"message"`
+}
+
+type CheckResult struct {
+	Passed          bool             `json:"passed"`
+	TotalCostUSD    float64          `json:"total_cost_usd"`
+	BaselineCostUSD float64          `json:"baseline_cost_usd,omitempty"`
+	GrowthPercent   float64          `json:"growth_percent,omitempty"`
+	Thresholds      CheckThresholds  `json:"thresholds"`
+	Violations      []CheckViolation `json:"violations"`
+}
@@ -0,0 +1,42 @@
+package types
+
+const (
+	OverheadProportional = "proportional"
+	OverheadEven         = "even"
+	OverheadWeighted     = "weighted"
+)
+
+// ClusterCost describes what the cluster's nodes cost, so that system pods,
+// daemonsets and unrequested capacity can be charged to the workloads.
+type ClusterCost struct {
+	Nodes []NodePool `json:"nodes,omitempty"`
+	// NodeCostUSD overrides the monthly cost derived from Nodes, e.g. with
+	// the figure from the cloud bill.
+	NodeCostUSD float64 `json:"node_cost_usd,omitempty"`
+	// SharedResources are overhead workloads such as ingress controllers.
+	// DaemonSets are always treated as shared.
+	SharedResources []string `json:"shared_resources,omitempty"`
+	Strategy        string   `json:"strategy,omitempty"`
+	// Weights are per-resource shares for the weighted strategy.
+	Weights map[string]float64 `json:"weights,omitempty"`
+}
+
+// OverheadInfo is a workload's share of shared and idle cluster cost, or
+// marks a shared workload whose own cost was spread over the others.
+type OverheadInfo struct {
+	Shared           bool    `json:"shared,omitempty"`
+	SharedCostUSD    float64 `json:"shared_cost_usd,omitempty"`
+	IdleCostUSD      float64 `json:"idle_cost_usd,omitempty"`
+	AllocatedCostUSD float64 `json:"allocated_cost_usd"`
+}
+
+// ClusterSummary splits the node bill into workload requests, shared
+// workloads and idle capacity. Idle is negative when requests priced at
+// catalog rates exceed the bill.
+type ClusterSummary struct {
+	Strategy        string  `json:"strategy"`
+	NodeCostUSD     float64 `json:"node_cost_usd"`
+	WorkloadCostUSD float64 `json:"workload_cost_usd"`
+	SharedCostUSD   float64 `json:"shared_cost_usd"`
+	IdleCostUSD     float64 `json:"idle_cost_usd"`
+}
@@ -0,0 +1,71 @@
+package types
+
+const (
+	ChangeAdded     = "added"
+	ChangeRemoved   = "removed"
+	ChangeChanged   = "changed"
+	ChangeUnchanged = "unchanged"
+)
+
+type ResourceChange struct {
+	Provider Provider `json:"provider"`
+	Resource string   `json:"resource"`
+	Status   string   `json:"status"`
+
+	BaseCostUSD     float64 `json:"base_cost_usd"`
+	HeadCostUSD     float64 `json:"head_cost_usd"`
+	CostDeltaUSD    float64 `json:"cost_delta_usd"`
+	BaseSavingsUSD  float64 `json:"base_savings_usd"`
+	HeadSavingsUSD  float64 `json:"head_savings_usd"`
+	SavingsDeltaUSD float64 `json:"savings_delta_usd"`
+	BaseWastePct    float64 `json:"base_waste_percentage"`
+	HeadWastePct    float64 `json:"head_waste_percentage"`
+
+	BaseRequested Requests `json:"base_requested"`
+	HeadRequested Requests `json:"head_requested"`
+	BaseUsageP95  Requests `json:"base_usage_p95"`
+	HeadUsageP95  Requests `json:"head_usage_p95"`
+
+	BaseInstanceType string `json:"base_instance_type,omitempty"`
+	HeadInstanceType string `json:"head_instance_type,omitempty"`
+}
+
+// AppliedFix is a fix that took effect between two scans. Source is
+// "history" when the fix was recorded as applied and "inferred" when the
+// head scan matches what the base scan recommended.
+type AppliedFix struct {
+	ActionID            string  `json:"action_id,omitempty"`
+	Resource            string  `json:"resource"`
+	Intent              string  `json:"intent"`
+	Description         string  `json:"description"`
+	EstimatedSavingsUSD float64 `json:"estimated_savings_usd,omitempty"`
+	Source              string  `json:"source"`
+}
+
+type ScanDiffSummary struct {
+	BaseCostUSD     float64 `json:"base_cost_usd"`
+	HeadCostUSD     float64 `json:"head_cost_usd"`
+	CostDeltaUSD    float64 `json:"cost_delta_usd"`
+	BaseSavingsUSD  float64 `json:"base_savings_usd"`
+	HeadSavingsUSD  float64 `json:"head_savings_usd"`
+	SavingsDeltaUSD float64 `json:"savings_delta_usd"`
+	Added           int     `json:"added"`
+	Removed         int     `json:"removed"`
+	Changed         int     `json:"changed"`
+}
+
+type ScanDiff struct {
+	BaseID       string           `json:"base_id,omitempty"`
+	HeadID       string           `json:"head_id,omitempty"`
+	Summary      ScanDiffSummary  `json:"summary"`
+	Resources    []ResourceChange `json:"resources"`
+	FixesApplied []AppliedFix     `json:"fixes_applied"`
+}
+
+// ScanDiffRequest compares two scans given inline or by history ID.
+type ScanDiffRequest struct {
+	Base   *ScanResponse `json:"base,omitempty"`
+	Head   *ScanResponse `json:"head,omitempty"`
+	BaseID string        `json:"base_id,omitempty"`
+	HeadID string        `json:"head_id,omitempty"`
+}
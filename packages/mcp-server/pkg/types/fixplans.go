@@ -1,6 +1,7 @@
 package types
 
 type FixAction struct {
+	ID          string   `json:"id"`
 	Provider    Provider `json:"provider"`
 	Resource    string   `json:"resource"`
 	Intent      string   `json:"intent"`
@@ -12,19 +13,27 @@ type FixAction struct {
 	AIGuidance  string   `json:"ai_guidance"`
 
 	EstimatedSavingsUSD float64 `json:"estimated_savings_usd"`
+
+	RiskLevel        string  `json:"risk_level,omitempty"`
+	RiskCategory     string  `json:"risk_category,omitempty"`
+	RequiresApproval bool    `json:"requires_approval,omitempty"`
+	LastActive       []int64 `json:"last_active,omitempty"`
 }
 
 type FixOperation struct {
 	Field     string  `json:"field"`
 	Operation string  `json:"operation"`
+	Current   float64 `json:"current"`
 	Value     float64 `json:"value"`
 	Unit      string  `json:"unit"`
+	Target    string  `json:"target,omitempty"`
 }
 
 type FixPlanRequest struct {
 	AggregatedMetrics []AggregatedMetrics `json:"aggregated_metrics"`
 	BudgetTarget      float64             `json:"budget_target_usd,omitempty"`
 	AutoApprove       bool                `json:"auto_approve,omitempty"`
+	Recommender       string              `json:"recommender,omitempty"`
 }
 
 type FixPlanResponse struct {
@@ -35,7 +44,10 @@ type FixPlanResponse struct {
 	MeetsBudget      bool        `json:"meets_budget"`
 	RequiresApproval bool        `json:"requires_approval"`
 	Actions          []FixAction `json:"actions"`
-	Summary          string      `json:"summary"`
+	// Held lists resources left out of the plan because an anomaly is
+	// still distorting their usage.
+	Held    []string `json:"held,omitempty"`
+	Summary string   `json:"summary"`
 }
 
 type AIDecision struct {
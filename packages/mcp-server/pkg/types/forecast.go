@@ -0,0 +1,40 @@
+package types
+
+import "time"
+
+const (
+	BudgetOK     = "ok"
+	BudgetAtRisk = "at_risk"
+	BudgetBreach = "breach"
+)
+
+// ForecastPoint is the projected average monthly cost over one future month.
+// Lower and upper bound the prediction interval.
+type ForecastPoint struct {
+	Month    int       `json:"month"`
+	Start    time.Time `json:"start"`
+	CostUSD  float64   `json:"cost_usd"`
+	LowerUSD float64   `json:"lower_usd"`
+	UpperUSD float64   `json:"upper_usd"`
+	Budget   string    `json:"budget,omitempty"`
+}
+
+type SeriesForecast struct {
+	Key              string          `json:"key"`
+	Provider         Provider        `json:"provider,omitempty"`
+	DataPoints       int             `json:"data_points"`
+	LastCostUSD      float64         `json:"last_cost_usd"`
+	TrendUSDPerMonth float64         `json:"trend_usd_per_month"`
+	Seasonal         bool            `json:"seasonal"`
+	Months           []ForecastPoint `json:"months"`
+}
+
+type ForecastResponse struct {
+	Horizon      int              `json:"horizon_months"`
+	Confidence   float64          `json:"confidence"`
+	BudgetTarget float64          `json:"budget_target_usd,omitempty"`
+	Total        SeriesForecast   `json:"total"`
+	Resources    []SeriesForecast `json:"resources"`
+	// Warnings name series that had too little history to fit.
+	Warnings []string `json:"warnings,omitempty"`
+}
@@ -0,0 +1,43 @@
+package types
+
+// ManifestCost is the declared cost of one workload or instance found in
+// manifests. Requests are per replica; MonthlyCostUSD covers all replicas.
+type ManifestCost struct {
+	Kind           string   `json:"kind"`
+	Name           string   `json:"name"`
+	Namespace      string   `json:"namespace,omitempty"`
+	File           string   `json:"file"`
+	Provider       Provider `json:"provider"`
+	Replicas       int      `json:"replicas"`
+	Requests       Requests `json:"requests,omitempty"`
+	InstanceType   string   `json:"instance_type,omitempty"`
+	MonthlyCostUSD float64  `json:"monthly_cost_usd"`
+}
+
+type ManifestChange struct {
+	Kind         string        `json:"kind"`
+	Name         string        `json:"name"`
+	Namespace    string        `json:"namespace,omitempty"`
+	Dir          string        `json:"dir,omitempty"`
+	Status       string        `json:"status"`
+	Base         *ManifestCost `json:"base,omitempty"`
+	Head         *ManifestCost `json:"head,omitempty"`
+	CostDeltaUSD float64       `json:"cost_delta_usd"`
+}
+
+type CostImpact struct {
+	BaseCostUSD  float64          `json:"base_cost_usd"`
+	HeadCostUSD  float64          `json:"head_cost_usd"`
+	DeltaUSD     float64          `json:"delta_usd"`
+	DeltaPercent float64          `json:"delta_percent"`
+	Changes      []ManifestChange `json:"changes"`
+	// Unpriced lists resources whose cost could not be computed,
+	// e.g. an instance type missing from the catalog or set by a variable.
+	Unpriced []string `json:"unpriced,omitempty"`
+}
+
+// ImpactRequest carries the base and head manifests as file path → content.
+type ImpactRequest struct {
+	Base map[string]string `json:"base"`
+	Head map[string]string `json:"head"`
+}
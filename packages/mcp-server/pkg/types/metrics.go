@@ -7,6 +7,20 @@ const (
 	ProviderAWSLambda  Provider = "aws_lambda"
 	ProviderAWSEC2     Provider = "aws_ec2"
 	ProviderVercel     Provider = "vercel"
+
+	ProviderKubernetesStorage  Provider = "kubernetes_storage"
+	ProviderGCPCloudRun        Provider = "gcp_cloud_run"
+	ProviderGCPGCE             Provider = "gcp_gce"
+	ProviderAzureContainerApps Provider = "azure_container_apps"
+	ProviderAzureVM            Provider = "azure_vm"
+	ProviderAWSECSFargate      Provider = "aws_ecs_fargate"
+	ProviderAWSRDS             Provider = "aws_rds"
+	ProviderAWSS3              Provider = "aws_s3"
+	// ProviderObjectStorage covers S3-compatible stores other than AWS.
+	ProviderObjectStorage Provider = "object_storage"
+	// ProviderNetworkFlow samples carry traffic from the resource to one
+	// destination; their cost is attributed to the source resource.
+	ProviderNetworkFlow Provider = "network_flow"
 )
 
 type MetricCollection struct {
@@ -14,12 +28,22 @@ type MetricCollection struct {
 	Resource  string          `json:"resource"`
 	TimeStamp int64           `json:"timestamp"`
 	Metrics   ResourceMetrics `json:"resource_metrics"`
+	// Labels are the resource's namespace/pod labels or cloud tags, used to
+	// allocate cost by team, product or environment.
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 type K8sResourceMetrics struct {
 	Resource string  `json:"resource,omitempty"`
 	CpuMilli float64 `json:"cpu_milli,omitempty"`
 	MemoryGB float64 `json:"memory_gb,omitempty"`
+
+	OOMKilled bool    `json:"oom_killed,omitempty"`
+	Replicas  float64 `json:"replicas,omitempty"`
+
+	// RequestCount is the number of requests served since the previous
+	// sample. Nil means the workload reports no traffic metrics at all.
+	RequestCount *float64 `json:"request_count,omitempty"`
 }
 
 type LambdaResourceMetrics struct {
@@ -28,9 +52,24 @@ type LambdaResourceMetrics struct {
 }
 
 type VMResourceMetrics struct {
-	CpuPercent float64 `json:"cpu_percent,omitempty"`
-	NetworkGB  float64 `json:"network_gb,omitempty"`
-	DiskGB     float64 `json:"disk_gb,omitempty"`
+	CpuPercent    float64 `json:"cpu_percent,omitempty"`
+	MemoryPercent float64 `json:"memory_percent,omitempty"`
+	NetworkGB     float64 `json:"network_gb,omitempty"`
+	DiskGB        float64 `json:"disk_gb,omitempty"`
+	InstanceType  string  `json:"instance_type,omitempty"`
+}
+
+type CloudRunResourceMetrics struct {
+	RequestsPerSecond float64 `json:"requests_per_second,omitempty"`
+	// Instances is the average number of container instances running over
+	// the sample interval, i.e. billable container instance time per second.
+	Instances         float64 `json:"instances,omitempty"`
+	CpuUtilization    float64 `json:"cpu_utilization,omitempty"`
+	MemoryUtilization float64 `json:"memory_utilization,omitempty"`
+	MinInstances      float64 `json:"min_instances,omitempty"`
+	CpuLimit          float64 `json:"cpu_limit,omitempty"`
+	MemoryLimitGB     float64 `json:"memory_limit_gb,omitempty"`
+	Concurrency       float64 `json:"concurrency,omitempty"`
 }
 
 type VercelResourceMetrics struct {
@@ -38,11 +77,99 @@ type VercelResourceMetrics struct {
 	ColdStarts float64 `json:"cold_starts,omitempty"`
 }
 
+type PVCResourceMetrics struct {
+	CapacityGB   float64 `json:"capacity_gb,omitempty"`
+	UsedGB       float64 `json:"used_gb,omitempty"`
+	StorageClass string  `json:"storage_class,omitempty"`
+	Phase        string  `json:"phase,omitempty"`
+	// MountedBy is the pod using the claim. Nil means the collector does
+	// not report mounts; an empty string means no pod mounts it.
+	MountedBy *string `json:"mounted_by,omitempty"`
+	// Orphaned is set by collectors that determine orphaned claims
+	// themselves.
+	Orphaned  bool   `json:"orphaned,omitempty"`
+	OwnerKind string `json:"owner_kind,omitempty"`
+	Owner     string `json:"owner,omitempty"`
+}
+
+type ContainerAppsResourceMetrics struct {
+	// VCPUSeconds and GiBSeconds are the billed allocation consumed since
+	// the previous sample.
+	VCPUSeconds   float64 `json:"vcpu_seconds,omitempty"`
+	GiBSeconds    float64 `json:"gib_seconds,omitempty"`
+	Replicas      float64 `json:"replicas,omitempty"`
+	CpuUsage      float64 `json:"cpu_usage,omitempty"`
+	MemoryUsageGB float64 `json:"memory_usage_gb,omitempty"`
+	CpuLimit      float64 `json:"cpu_limit,omitempty"`
+	MemoryLimitGB float64 `json:"memory_limit_gb,omitempty"`
+	MinReplicas   float64 `json:"min_replicas,omitempty"`
+}
+
+type ECSTaskResourceMetrics struct {
+	// CpuUtilization and MemoryUtilization are percentages of the task size.
+	CpuUtilization    float64 `json:"cpu_utilization,omitempty"`
+	MemoryUtilization float64 `json:"memory_utilization,omitempty"`
+	TaskCpu           float64 `json:"task_cpu,omitempty"`
+	TaskMemoryMiB     float64 `json:"task_memory_mib,omitempty"`
+	RunningTasks      float64 `json:"running_tasks,omitempty"`
+}
+
+type RDSResourceMetrics struct {
+	CpuPercent         float64 `json:"cpu_percent,omitempty"`
+	FreeableMemoryGB   float64 `json:"freeable_memory_gb,omitempty"`
+	Connections        float64 `json:"connections,omitempty"`
+	ReadIOPS           float64 `json:"read_iops,omitempty"`
+	WriteIOPS          float64 `json:"write_iops,omitempty"`
+	InstanceClass      string  `json:"instance_class,omitempty"`
+	StorageType        string  `json:"storage_type,omitempty"`
+	AllocatedStorageGB float64 `json:"allocated_storage_gb,omitempty"`
+	ProvisionedIOPS    float64 `json:"provisioned_iops,omitempty"`
+	MultiAZ            bool    `json:"multi_az,omitempty"`
+}
+
+type ObjectStorageResourceMetrics struct {
+	// StorageGB is the bucket size by storage class, e.g. STANDARD.
+	StorageGB    map[string]float64 `json:"storage_gb,omitempty"`
+	AgeHistogram []ObjectAgeBucket  `json:"age_histogram,omitempty"`
+
+	// Request counts and downloaded bytes since the previous sample.
+	GetRequests           float64 `json:"get_requests,omitempty"`
+	PutRequests           float64 `json:"put_requests,omitempty"`
+	DownloadedGB          float64 `json:"downloaded_gb,omitempty"`
+	IncompleteMultipartGB float64 `json:"incomplete_multipart_gb,omitempty"`
+}
+
+// ObjectAgeBucket is the size of objects aged [MinDays, MaxDays). A zero
+// MaxDays means no upper bound.
+type ObjectAgeBucket struct {
+	MinDays int     `json:"min_days"`
+	MaxDays int     `json:"max_days,omitempty"`
+	SizeGB  float64 `json:"size_gb"`
+}
+
+type FlowResourceMetrics struct {
+	// Destination is the receiving workload, or empty (or "internet") for
+	// traffic leaving the cloud.
+	Destination       string  `json:"destination,omitempty"`
+	SourceZone        string  `json:"source_zone,omitempty"`
+	DestinationZone   string  `json:"destination_zone,omitempty"`
+	SourceRegion      string  `json:"source_region,omitempty"`
+	DestinationRegion string  `json:"destination_region,omitempty"`
+	TransferGB        float64 `json:"transfer_gb,omitempty"`
+}
+
 type ResourceMetrics struct {
-	K8sResourceMetrics    K8sResourceMetrics    `json:"k8s_resource,omitempty"`
-	LambdaResourceMetrics LambdaResourceMetrics `json:"lambda_resource,omitempty"`
-	VMResourceMetrics     VMResourceMetrics     `json:"vm_resource,omitempty"`
-	VercelResourceMetrics VercelResourceMetrics `json:"vercel_resource,omitempty"`
+	K8sResourceMetrics           K8sResourceMetrics           `json:"k8s_resource,omitempty"`
+	LambdaResourceMetrics        LambdaResourceMetrics        `json:"lambda_resource,omitempty"`
+	VMResourceMetrics            VMResourceMetrics            `json:"vm_resource,omitempty"`
+	VercelResourceMetrics        VercelResourceMetrics        `json:"vercel_resource,omitempty"`
+	PVCResourceMetrics           PVCResourceMetrics           `json:"pvc_resource,omitempty"`
+	CloudRunResourceMetrics      CloudRunResourceMetrics      `json:"cloud_run_resource,omitempty"`
+	ContainerAppsResourceMetrics ContainerAppsResourceMetrics `json:"container_apps_resource,omitempty"`
+	ECSTaskResourceMetrics       ECSTaskResourceMetrics       `json:"ecs_task_resource,omitempty"`
+	RDSResourceMetrics           RDSResourceMetrics           `json:"rds_resource,omitempty"`
+	ObjectStorageResourceMetrics ObjectStorageResourceMetrics `json:"object_storage_resource,omitempty"`
+	FlowResourceMetrics          FlowResourceMetrics          `json:"flow_resource,omitempty"`
 }
 
 type MetricStat struct {
@@ -54,6 +181,7 @@ type MetricStat struct {
 type AggregatedMetrics struct {
 	Provider Provider              `json:"provider"`
 	Resource string                `json:"resource"`
+	Labels   map[string]string     `json:"labels,omitempty"`
 	Metrics  map[string]MetricStat `json:"metrics"`
 
 	RequestedCpuMilli float64 `json:"requested_cpu_milli"`
@@ -61,6 +189,20 @@ type AggregatedMetrics struct {
 
 	OptimalCpuMilli float64 `json:"optimal_cpu_milli"`
 	OptimalMemoryGB float64 `json:"optimal_memory_gb"`
+	Recommender     string  `json:"recommender,omitempty"`
+
+	Bounds     *RecommendationBounds `json:"bounds,omitempty"`
+	Autoscaler *HPAConfig            `json:"autoscaler,omitempty"`
+	Idle       *IdleReport           `json:"idle,omitempty"`
+	Workload   *WorkloadInfo         `json:"workload,omitempty"`
+	Storage    *StorageInfo          `json:"storage,omitempty"`
+	Serverless *ServerlessInfo       `json:"serverless,omitempty"`
+	Machine    *MachineInfo          `json:"machine,omitempty"`
+	Database   *DatabaseInfo         `json:"database,omitempty"`
+	Bucket     *BucketInfo           `json:"bucket,omitempty"`
+	Network    *NetworkInfo          `json:"network,omitempty"`
+	Anomalies  []Anomaly             `json:"anomalies,omitempty"`
+	Overhead   *OverheadInfo         `json:"overhead,omitempty"`
 
 	CostCurrentUSD float64 `json:"cost_current_usd"`
 	CostOptimalUSD float64 `json:"cost_optimal_usd"`
@@ -69,6 +211,126 @@ type AggregatedMetrics struct {
 	DataPoints int `json:"data_points"`
 }
 
+type RecommendationBounds struct {
+	Lower Requests `json:"lower"`
+	Upper Requests `json:"upper"`
+}
+
+type HPAConfig struct {
+	MinReplicas          int     `json:"min_replicas"`
+	MaxReplicas          int     `json:"max_replicas"`
+	TargetCPUUtilization float64 `json:"target_cpu_utilization,omitempty"`
+}
+
+type WorkloadInfo struct {
+	Kind     string `json:"kind"`
+	Replicas int    `json:"replicas,omitempty"`
+	HasPDB   bool   `json:"has_pdb,omitempty"`
+	NodePool string `json:"node_pool,omitempty"`
+	OnSpot   bool   `json:"on_spot,omitempty"`
+	// HasVolumes is set when the pods mount PersistentVolumeClaims, which
+	// may be bound to a zone the target nodes are not in.
+	HasVolumes bool `json:"has_volumes,omitempty"`
+}
+
+type StorageInfo struct {
+	StorageClass          string  `json:"storage_class"`
+	RecommendedClass      string  `json:"recommended_class"`
+	CapacityGB            float64 `json:"capacity_gb"`
+	RecommendedCapacityGB float64 `json:"recommended_capacity_gb"`
+	Phase                 string  `json:"phase,omitempty"`
+	OwnerKind             string  `json:"owner_kind,omitempty"`
+	Owner                 string  `json:"owner,omitempty"`
+	Unbound               bool    `json:"unbound,omitempty"`
+	Orphaned              bool    `json:"orphaned,omitempty"`
+}
+
+// DatabaseInfo is the storage and connection side of a managed database;
+// the instance class itself is described by MachineInfo.
+type DatabaseInfo struct {
+	MultiAZ                   bool    `json:"multi_az,omitempty"`
+	StorageType               string  `json:"storage_type"`
+	RecommendedStorageType    string  `json:"recommended_storage_type"`
+	AllocatedStorageGB        float64 `json:"allocated_storage_gb"`
+	ProvisionedIOPS           float64 `json:"provisioned_iops,omitempty"`
+	RecommendedIOPS           float64 `json:"recommended_iops,omitempty"`
+	MaxConnections            float64 `json:"max_connections"`
+	RecommendedMaxConnections float64 `json:"recommended_max_connections"`
+}
+
+// BucketInfo summarises an object storage bucket and the lifecycle rules
+// CostGuard recommends for it.
+type BucketInfo struct {
+	StorageGB             map[string]float64 `json:"storage_gb"`
+	IncompleteMultipartGB float64            `json:"incomplete_multipart_gb,omitempty"`
+	MonthlyGetRequests    float64            `json:"monthly_get_requests"`
+	MonthlyDownloadedGB   float64            `json:"monthly_downloaded_gb"`
+	RecommendedRules      []LifecycleRule    `json:"recommended_rules,omitempty"`
+}
+
+// LifecycleRule transitions objects older than Days to StorageClass, or
+// aborts incomplete multipart uploads after Days when StorageClass is empty.
+type LifecycleRule struct {
+	ID           string  `json:"id"`
+	Days         int     `json:"days"`
+	StorageClass string  `json:"storage_class,omitempty"`
+	SizeGB       float64 `json:"size_gb"`
+	SavingsUSD   float64 `json:"savings_usd"`
+}
+
+// NetworkInfo is the monthly data transfer billed to a resource, split by
+// where the traffic goes.
+type NetworkInfo struct {
+	InternetGB    float64       `json:"internet_gb"`
+	CrossZoneGB   float64       `json:"cross_zone_gb"`
+	CrossRegionGB float64       `json:"cross_region_gb"`
+	CostUSD       float64       `json:"cost_usd"`
+	Flows         []NetworkFlow `json:"flows,omitempty"`
+}
+
+type NetworkFlow struct {
+	Destination string  `json:"destination"`
+	Kind        string  `json:"kind"`
+	MonthlyGB   float64 `json:"monthly_gb"`
+	CostUSD     float64 `json:"cost_usd"`
+	// TopologyAware is set when keeping this flow in-zone is recommended.
+	TopologyAware bool `json:"topology_aware,omitempty"`
+}
+
+// ServerlessInfo is the configuration of a serverless container service
+// (Cloud Run, Container Apps) next to what CostGuard recommends for it.
+type ServerlessInfo struct {
+	CpuLimit                 float64 `json:"cpu_limit"`
+	MemoryLimitGB            float64 `json:"memory_limit_gb"`
+	MinInstances             float64 `json:"min_instances"`
+	Concurrency              float64 `json:"concurrency"`
+	RecommendedCpuLimit      float64 `json:"recommended_cpu_limit"`
+	RecommendedMemoryLimitGB float64 `json:"recommended_memory_limit_gb"`
+	RecommendedMinInstances  float64 `json:"recommended_min_instances"`
+	RecommendedConcurrency   float64 `json:"recommended_concurrency"`
+}
+
+// MachineInfo describes a fixed-size instance (VM, database, task) and the
+// size CostGuard recommends instead.
+type MachineInfo struct {
+	InstanceType        string  `json:"instance_type"`
+	VCPU                float64 `json:"vcpu"`
+	MemoryGB            float64 `json:"memory_gb"`
+	RecommendedType     string  `json:"recommended_type"`
+	RecommendedVCPU     float64 `json:"recommended_vcpu"`
+	RecommendedMemoryGB float64 `json:"recommended_memory_gb"`
+
+	HourlyUSD            float64 `json:"hourly_usd,omitempty"`
+	RecommendedHourlyUSD float64 `json:"recommended_hourly_usd,omitempty"`
+}
+
+type IdleReport struct {
+	Zombie     bool    `json:"zombie"`
+	Reason     string  `json:"reason"`
+	IdleSince  int64   `json:"idle_since"`
+	LastActive []int64 `json:"last_active"`
+}
+
 type Requests struct {
 	CpuMilli float64 `json:"cpu_milli"`
 	MemoryGB float64 `json:"memory_gb"`
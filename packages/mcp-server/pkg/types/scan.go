@@ -10,12 +10,27 @@ type ScanResourceCost struct {
 type ScanResource struct {
 	Provider  Provider              `json:"provider"`
 	Resource  string                `json:"resource"`
+	Labels    map[string]string     `json:"labels,omitempty"`
 	Usage     map[string]MetricStat `json:"usage"`
 	Requested struct {
 		CpuMilli float64 `json:"cpu_milli"`
 		MemoryGB float64 `json:"memory_gb"`
 	} `json:"requested"`
-	Costs ScanResourceCost `json:"costs"`
+	Recommended Requests              `json:"recommended"`
+	Recommender string                `json:"recommender,omitempty"`
+	Bounds      *RecommendationBounds `json:"bounds,omitempty"`
+	Autoscaler  *HPAConfig            `json:"autoscaler,omitempty"`
+	Idle        *IdleReport           `json:"idle,omitempty"`
+	Workload    *WorkloadInfo         `json:"workload,omitempty"`
+	Storage     *StorageInfo          `json:"storage,omitempty"`
+	Serverless  *ServerlessInfo       `json:"serverless,omitempty"`
+	Machine     *MachineInfo          `json:"machine,omitempty"`
+	Database    *DatabaseInfo         `json:"database,omitempty"`
+	Bucket      *BucketInfo           `json:"bucket,omitempty"`
+	Network     *NetworkInfo          `json:"network,omitempty"`
+	Anomalies   []Anomaly             `json:"anomalies,omitempty"`
+	Overhead    *OverheadInfo         `json:"overhead,omitempty"`
+	Costs       ScanResourceCost      `json:"costs"`
 }
 
 type ScanSummary struct {
@@ -23,6 +38,9 @@ type ScanSummary struct {
 	TotalOptimalCostUSD      float64  `json:"total_optimal_cost_usd"`
 	TotalPotentialSavingsUSD float64  `json:"total_potential_savings_usd"`
 	TopOffenders             []string `json:"top_offenders"`
+	// Cluster is set when node cost was given; totals then include idle
+	// capacity and reconcile to the node bill.
+	Cluster *ClusterSummary `json:"cluster,omitempty"`
 }
 
 type ScanResponse struct {
@@ -31,7 +49,11 @@ type ScanResponse struct {
 }
 
 type ScanRequest struct {
-	Metrics        []MetricCollection  `json:"metrics"`
-	ActualRequests map[string]Requests `json:"actual_requests,omitempty"`
-	Paths          []string            `json:"paths,omitempty"`
+	Metrics        []MetricCollection      `json:"metrics"`
+	ActualRequests map[string]Requests     `json:"actual_requests,omitempty"`
+	Autoscalers    map[string]HPAConfig    `json:"autoscalers,omitempty"`
+	Workloads      map[string]WorkloadInfo `json:"workloads,omitempty"`
+	Paths          []string                `json:"paths,omitempty"`
+	Recommender    string                  `json:"recommender,omitempty"`
+	Cluster        *ClusterCost            `json:"cluster,omitempty"`
 }
@@ -0,0 +1,40 @@
+package types
+
+type NodePool struct {
+	InstanceType        string  `json:"instance_type"`
+	AllocatableCpuMilli float64 `json:"allocatable_cpu_milli"`
+	AllocatableMemoryGB float64 `json:"allocatable_memory_gb"`
+	MonthlyPriceUSD     float64 `json:"monthly_price_usd"`
+	Count               int     `json:"count"`
+}
+
+type PodPlacement struct {
+	Resource    string   `json:"resource"`
+	Replicas    int      `json:"replicas"`
+	Current     Requests `json:"current"`
+	Recommended Requests `json:"recommended"`
+}
+
+type SimulationRequest struct {
+	Nodes []NodePool     `json:"nodes"`
+	Pods  []PodPlacement `json:"pods,omitempty"`
+	Scan  *ScanResponse  `json:"scan,omitempty"`
+}
+
+type SimulationResult struct {
+	NodesUsed     int            `json:"nodes_used"`
+	NodesByType   map[string]int `json:"nodes_by_type"`
+	CostUSD       float64        `json:"cost_usd"`
+	Unschedulable []string       `json:"unschedulable,omitempty"`
+}
+
+type SimulationResponse struct {
+	FleetNodes       int              `json:"fleet_nodes"`
+	FleetCostUSD     float64          `json:"fleet_cost_usd"`
+	Current          SimulationResult `json:"current"`
+	Recommended      SimulationResult `json:"recommended"`
+	NodesDrained     int              `json:"nodes_drained"`
+	CostDeltaUSD     float64          `json:"cost_delta_usd"`
+	PerPodSavingsUSD float64          `json:"per_pod_savings_usd"`
+	Summary          string           `json:"summary"`
+}
@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"math"
 	"sort"
 
@@ -12,19 +15,31 @@ func ConvertScanToAggregated(res types.ScanResponse) []types.AggregatedMetrics {
 
 	for _, r := range res.Resources {
 
-		usage := map[string]types.MetricStat{
-			"cpu":    r.Usage["cpu_milli"],
-			"memory": r.Usage["memory_gb"],
-		}
-
 		agg := types.AggregatedMetrics{
 			Provider: r.Provider,
 			Resource: r.Resource,
-			Metrics:  usage,
+			Labels:   r.Labels,
+			Metrics:  r.Usage,
 
 			RequestedCpuMilli: r.Requested.CpuMilli,
 			RequestedMemoryGB: r.Requested.MemoryGB,
 
+			OptimalCpuMilli: r.Recommended.CpuMilli,
+			OptimalMemoryGB: r.Recommended.MemoryGB,
+			Recommender:     r.Recommender,
+			Bounds:          r.Bounds,
+			Autoscaler:      r.Autoscaler,
+			Idle:            r.Idle,
+			Workload:        r.Workload,
+			Storage:         r.Storage,
+			Serverless:      r.Serverless,
+			Machine:         r.Machine,
+			Database:        r.Database,
+			Bucket:          r.Bucket,
+			Network:         r.Network,
+			Anomalies:       r.Anomalies,
+			Overhead:        r.Overhead,
+
 			CostCurrentUSD: r.Costs.CurrentCostUSD,
 			CostOptimalUSD: r.Costs.OptimalCostUSD,
 			CostSavingsUSD: r.Costs.PotentialSavingsUSD,
@@ -57,6 +72,14 @@ func ComputePercentile(data []float64, percentile float64) float64 {
 	return float64(data[finalRank])
 }
 
+func ComputeStat(data []float64) types.MetricStat {
+	return types.MetricStat{
+		P50: ComputePercentile(data, 50),
+		P95: ComputePercentile(data, 95),
+		Avg: CalculateAvg(data),
+	}
+}
+
 func CalculateAvg(data []float64) float64 {
 	if len(data) == 0 {
 		return 0.0
@@ -69,3 +92,17 @@ func CalculateAvg(data []float64) float64 {
 	}
 	return float64(sum) / float64(len(data))
 }
+
+// ActionID derives a stable identifier for a fix action from the resource it
+// targets, what it intends to do and the value or target it sets, so the
+// same change keeps the same ID across runs regardless of its position in a
+// plan.
+func ActionID(action types.FixAction) string {
+	key := fmt.Sprintf(
+		"%s|%s|%s|%s|%.4f%s|%s",
+		action.Provider, action.Resource, action.Intent,
+		action.Action.Field, action.Action.Value, action.Action.Unit, action.Action.Target,
+	)
+	sum := sha256.Sum256([]byte(key))
+	return "action-" + hex.EncodeToString(sum[:])[:12]
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tanay13/costguard/packages/mcp-server/pkg/types"
+)
+
+func TestActionID(t *testing.T) {
+	base := types.FixAction{
+		Provider: types.ProviderKubernetes,
+		Resource: "api",
+		Intent:   "reduce_cpu_request",
+		Action: types.FixOperation{
+			Field:     "resources.requests.cpu",
+			Operation: "set_to",
+			Current:   1000,
+			Value:     250,
+			Unit:      "m",
+		},
+	}
+
+	tests := []struct {
+		name   string
+		change func(a *types.FixAction)
+		same   bool
+	}{
+		{"identical", func(a *types.FixAction) {}, true},
+		{"description", func(a *types.FixAction) { a.Description = "other" }, true},
+		{"savings", func(a *types.FixAction) { a.EstimatedSavingsUSD = 42 }, true},
+		{"current value", func(a *types.FixAction) { a.Action.Current = 2000 }, true},
+		{"risk", func(a *types.FixAction) { a.RiskLevel = "high" }, true},
+		{"resource", func(a *types.FixAction) { a.Resource = "web" }, false},
+		{"intent", func(a *types.FixAction) { a.Intent = "reduce_memory_request" }, false},
+		{"field", func(a *types.FixAction) { a.Action.Field = "resources.limits.cpu" }, false},
+		{"value", func(a *types.FixAction) { a.Action.Value = 300 }, false},
+		{"unit", func(a *types.FixAction) { a.Action.Unit = "Mi" }, false},
+		{"target", func(a *types.FixAction) { a.Action.Target = "spot" }, false},
+		{"provider", func(a *types.FixAction) { a.Provider = types.ProviderKubernetesStorage }, false},
+	}
+
+	want := ActionID(base)
+	if !strings.HasPrefix(want, "action-") || len(want) != len("action-")+12 {
+		t.Fatalf("ActionID = %q, want action- and 12 hex digits", want)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := base
+			tt.change(&a)
+			got := ActionID(a)
+			if (got == want) != tt.same {
+				t.Errorf("ActionID = %q, base %q, want same=%v", got, want, tt.same)
+			}
+		})
+	}
+}
+
+func TestComputePercentile(t *testing.T) {
+	tests := []struct {
+		data []float64
+		p    float64
+		want float64
+	}{
+		{nil, 50, 0},
+		{[]float64{3, 1, 2}, 50, 2},
+		{[]float64{5, 1, 4, 2, 3}, 100, 5},
+		{[]float64{5, 1, 4, 2, 3}, 0, 1},
+		{[]float64{1, 2}, 101, 0},
+	}
+
+	for _, tt := range tests {
+		if got := ComputePercentile(tt.data, tt.p); got != tt.want {
+			t.Errorf("ComputePercentile(%v, %v) = %v, want %v", tt.data, tt.p, got, tt.want)
+		}
+	}
+}